@@ -0,0 +1,28 @@
+package csskit
+
+// ClassSet collects parsed classes as they are found, keeping only the
+// first occurrence of each, so that the memory used while scanning large
+// sources grows with the number of distinct classes.
+type ClassSet struct {
+	keys    map[string]struct{}
+	classes []RawCSSClass
+}
+
+func (s *ClassSet) Add(rcs ...RawCSSClass) {
+	if s.keys == nil {
+		s.keys = make(map[string]struct{})
+	}
+	for _, rc := range rcs {
+		key := getClassKey(rc)
+		if _, exists := s.keys[key]; exists {
+			continue
+		}
+		s.keys[key] = struct{}{}
+		s.classes = append(s.classes, rc)
+	}
+}
+
+// Classes returns the collected classes in the order they were added.
+func (s *ClassSet) Classes() []RawCSSClass {
+	return s.classes
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/igormichalak/csskit"
+	"github.com/igormichalak/csskit/extract"
+)
+
+func formatDiagnostic(d csskit.FileDiagnostic) string {
+	var sb strings.Builder
+	if errors.Is(d.Err, csskit.ErrNoMatchingPattern) {
+		fmt.Fprintf(&sb, "%s: unknown utility %q", d.Pos, d.Class)
+	} else {
+		fmt.Fprintf(&sb, "%s: invalid class %q: %v", d.Pos, d.Class, d.Err)
+	}
+	for i, s := range d.Suggestions {
+		switch i {
+		case 0:
+			sb.WriteString(", did you mean ")
+		case len(d.Suggestions) - 1:
+			sb.WriteString(" or ")
+		default:
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%q", s)
+	}
+	if len(d.Suggestions) > 0 {
+		sb.WriteString("?")
+	}
+	return sb.String()
+}
+
+func printDiagnostics(diags []csskit.FileDiagnostic) {
+	for _, d := range diags {
+		fmt.Println(formatDiagnostic(d))
+	}
+}
+
+// fileError shortens the errors of files that can't be opened.
+func fileError(err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return fmt.Errorf("can't open file %q", pathErr.Path)
+	}
+	return err
+}
+
+// walkFile calls fn for every literal extracted from the file at fp.
+func walkFile(cfg *csskit.Config, fp string, fn func(lit extract.Literal) error) error {
+	return fileError(extract.WalkFileWithOptions(fp, cfg.ExtractOptions(), fn))
+}
+
+// loadConfig loads the config file at fp. A missing file is only an error
+// if its path was given explicitly, otherwise the default config is used.
+func loadConfig(fp string, explicit bool) (*csskit.Config, error) {
+	cfg, err := csskit.LoadConfig(fp)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return csskit.DefaultConfig(), nil
+		}
+		var pathErr *fs.PathError
+		if errors.As(err, &pathErr) {
+			return nil, fmt.Errorf("can't open config file %q", pathErr.Path)
+		}
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func scanSafelist(cfg *csskit.Config, opts csskit.ScanOptions) csskit.FileResult {
+	lits := make([]extract.Literal, len(cfg.Safelist))
+	for i, class := range cfg.Safelist {
+		lits[i] = extract.Literal{Value: class, Pos: extract.Position{Filename: "safelist"}}
+	}
+	return csskit.ScanLiterals(cfg, lits, opts)
+}
+
+func generateCSS(cfg *csskit.Config, classes []csskit.RawCSSClass) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := csskit.GenerateCSSWithConfig(&buf, classes, cfg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeFileAtomic(fp string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(fp), "."+filepath.Base(fp)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, fp); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
+
+func printErrors(err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			printErrors(e)
+		}
+		return
+	}
+	fmt.Printf("%s.\n", err)
+}
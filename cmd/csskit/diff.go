@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContext = 3
+
+type editOp int
+
+const (
+	opEqual editOp = iota
+	opDelete
+	opInsert
+)
+
+type edit struct {
+	op   editOp
+	line string
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// maxDiffEdits bounds the number of edits searched for by diffLines,
+// as the trace it keeps grows with the square of that number.
+const maxDiffEdits = 1000
+
+// diffLines computes the shortest edit script turning a into b using
+// the Myers algorithm. Past maxDiffEdits, the lines between the common
+// prefix and suffix are replaced as a whole instead.
+func diffLines(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]edit, 0, len(a)+len(b)-prefix-suffix)
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{op: opEqual, line: line})
+	}
+	edits = append(edits, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{op: opEqual, line: line})
+	}
+	return edits
+}
+
+func myersDiff(a, b []string) []edit {
+	n, m := len(a), len(b)
+	maxD := min(n+m, maxDiffEdits)
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, d, k)
+			}
+		}
+	}
+
+	edits := make([]edit, 0, n+m)
+	for _, line := range a {
+		edits = append(edits, edit{op: opDelete, line: line})
+	}
+	for _, line := range b {
+		edits = append(edits, edit{op: opInsert, line: line})
+	}
+	return edits
+}
+
+// backtrack walks the trace backwards from the furthest reaching path
+// on diagonal k. Each trace entry holds the diagonals -d through d as
+// they were before step d.
+func backtrack(trace [][]int, a, b []string, d, k int) []edit {
+	x, y := len(a), len(b)
+	var edits []edit
+
+	for ; d > 0; d-- {
+		v := trace[d]
+		var prevK int
+		if k == -d || (k != d && v[d+k-1] < v[d+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[d+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{op: opEqual, line: a[x]})
+		}
+		if x == prevX {
+			y--
+			edits = append(edits, edit{op: opInsert, line: b[y]})
+		} else {
+			x--
+			edits = append(edits, edit{op: opDelete, line: a[x]})
+		}
+		k = prevK
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		edits = append(edits, edit{op: opEqual, line: a[x]})
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// unifiedDiff returns the differences between a and b in the unified
+// diff format, or an empty string if they are equal.
+func unifiedDiff(nameA, nameB, a, b string) string {
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	var sb strings.Builder
+	lineA, lineB := 1, 1
+
+	for i := 0; i < len(edits); {
+		if edits[i].op == opEqual {
+			lineA++
+			lineB++
+			i++
+			continue
+		}
+
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", nameA, nameB)
+		}
+
+		start := max(i-diffContext, 0)
+		for j := start; j < i; j++ {
+			lineA--
+			lineB--
+		}
+
+		end := i
+		for end < len(edits) {
+			if edits[end].op != opEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].op == opEqual {
+				run++
+			}
+			if run == len(edits) || run-end > 2*diffContext {
+				end = min(end+diffContext, len(edits))
+				break
+			}
+			end = run
+		}
+
+		var countA, countB int
+		for _, e := range edits[start:end] {
+			if e.op != opInsert {
+				countA++
+			}
+			if e.op != opDelete {
+				countB++
+			}
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(lineA, countA), hunkRange(lineB, countB))
+		for _, e := range edits[start:end] {
+			switch e.op {
+			case opEqual:
+				sb.WriteString(" ")
+			case opDelete:
+				sb.WriteString("-")
+			case opInsert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		lineA += countA
+		lineB += countB
+		i = end
+	}
+
+	return sb.String()
+}
+
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, count)
+	}
+}
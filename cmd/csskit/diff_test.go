@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// numberedLines returns the lines "1\n" through "n\n" with the given
+// lines replaced.
+func numberedLines(n int, replace map[int]string) string {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		if s, ok := replace[i]; ok {
+			sb.WriteString(s)
+		} else {
+			sb.WriteString(strconv.Itoa(i))
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{"both empty", "", "", ""},
+		{
+			"separate hunks",
+			numberedLines(16, nil),
+			numberedLines(16, map[int]string{3: "X", 12: "Y"}),
+			"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+X\n 4\n 5\n 6\n" +
+				"@@ -9,7 +9,7 @@\n 9\n 10\n 11\n-12\n+Y\n 13\n 14\n 15\n",
+		},
+		{
+			"merged hunks",
+			numberedLines(9, nil),
+			numberedLines(9, map[int]string{3: "X", 9: "Y"}),
+			"@@ -1,9 +1,9 @@\n 1\n 2\n-3\n+X\n 4\n 5\n 6\n 7\n 8\n-9\n+Y\n",
+		},
+		{
+			"gap of twice the context",
+			numberedLines(10, nil),
+			numberedLines(10, map[int]string{2: "X", 9: "Y"}),
+			"@@ -1,10 +1,10 @@\n 1\n-2\n+X\n 3\n 4\n 5\n 6\n 7\n 8\n-9\n+Y\n 10\n",
+		},
+		{
+			"gap of twice the context plus one",
+			numberedLines(11, nil),
+			numberedLines(11, map[int]string{2: "X", 10: "Y"}),
+			"@@ -1,5 +1,5 @@\n 1\n-2\n+X\n 3\n 4\n 5\n" +
+				"@@ -7,5 +7,5 @@\n 7\n 8\n 9\n-10\n+Y\n 11\n",
+		},
+		{
+			"no trailing newline",
+			"a\nb", "a\nc",
+			"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+		{
+			"trailing newline added",
+			"a\nb", "a\nb\n",
+			"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{"from empty", "", "a\nb\n", "@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"to empty", "a\nb\n", "", "@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{"insertion", "a\nc\n", "a\nb\nc\n", "@@ -1,2 +1,3 @@\n a\n+b\n c\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want
+			if want != "" {
+				want = "--- a\n+++ b\n" + want
+			}
+			if got := unifiedDiff("a", "b", tt.a, tt.b); got != want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesIsMinimal(t *testing.T) {
+	a := splitLines("a\nb\nc\na\nb\nb\na\n")
+	b := splitLines("c\nb\na\nb\na\nc\n")
+	edits := diffLines(a, b)
+
+	var gotA, gotB []string
+	changes := 0
+	for _, e := range edits {
+		if e.op != opInsert {
+			gotA = append(gotA, e.line)
+		}
+		if e.op != opDelete {
+			gotB = append(gotB, e.line)
+		}
+		if e.op != opEqual {
+			changes++
+		}
+	}
+	if !slices.Equal(gotA, a) || !slices.Equal(gotB, b) {
+		t.Fatalf("edits don't turn %q into %q: %+v", a, b, edits)
+	}
+	if changes != 5 {
+		t.Errorf("got %d changes, want 5", changes)
+	}
+}
+
+func TestDiffLinesRewrite(t *testing.T) {
+	const lines = 8000
+	var before, after strings.Builder
+	for i := range lines {
+		fmt.Fprintf(&before, ".a-%d {}\n", i)
+		fmt.Fprintf(&after, ".b-%d {}\n", i)
+	}
+	a := splitLines("head\n" + before.String() + "tail\n")
+	b := splitLines("head\n" + after.String() + "tail\n")
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	allocated := stats.TotalAlloc
+	edits := diffLines(a, b)
+	runtime.ReadMemStats(&stats)
+	if allocated := stats.TotalAlloc - allocated; allocated > 64<<20 {
+		t.Errorf("diffLines allocated %d MB", allocated>>20)
+	}
+
+	// Past maxDiffEdits, the differing lines are replaced as a whole
+	// while the common lines around them are kept.
+	if len(edits) != 2*lines+2 {
+		t.Fatalf("got %d edits, want %d", len(edits), 2*lines+2)
+	}
+	for i, e := range edits {
+		var want editOp
+		switch {
+		case i == 0 || i == len(edits)-1:
+			want = opEqual
+		case i <= lines:
+			want = opDelete
+		default:
+			want = opInsert
+		}
+		if e.op != want {
+			t.Fatalf("edit %d is %v, want %v", i, e.op, want)
+		}
+	}
+}
@@ -6,104 +6,190 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
-	"path/filepath"
+	"runtime"
+	"time"
 
 	"github.com/igormichalak/csskit"
 	"github.com/igormichalak/csskit/extract"
 )
 
 func main() {
+	var configFilepath string
 	var outFilepath string
 	var extractMode bool
-
-	flag.StringVar(&outFilepath, "out", "output.css", "output CSS filepath.")
+	var watchMode bool
+	var checkMode bool
+	var minify bool
+	var diagnose bool
+	var strict bool
+	var watchInterval time.Duration
+	var jobs int
+
+	flag.StringVar(&configFilepath, "config", csskit.DefaultConfigFilename, "config filepath.")
+	flag.StringVar(&outFilepath, "out", "output.css", "output CSS filepath (overrides config).")
 	flag.BoolVar(&extractMode, "extracted", false, "only prints extracted tokens.")
+	flag.BoolVar(&checkMode, "check", false, "fails if the output file is not up to date.")
+	flag.BoolVar(&minify, "minify", false, "minifies the output (overrides config).")
+	flag.BoolVar(&diagnose, "diagnostics", false, "reports class-like strings that match no pattern.")
+	flag.BoolVar(&strict, "strict", false, "fails if there are unknown utilities (implies -diagnostics).")
+	flag.BoolVar(&watchMode, "watch", false, "regenerates the output when source files change.")
+	flag.DurationVar(&watchInterval, "interval", 500*time.Millisecond, "polling interval in watch mode.")
+	flag.IntVar(&jobs, "j", runtime.GOMAXPROCS(0), "number of files processed in parallel.")
 	flag.Parse()
 
+	diagnose = diagnose || strict
+
+	setFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
+	cfg, err := loadConfig(configFilepath, setFlags["config"])
+	if err != nil {
+		fmt.Printf("%s.\n", err)
+		os.Exit(1)
+	}
+	if setFlags["out"] {
+		cfg.Output = outFilepath
+	}
+	outFilepath = cfg.Output
+	if setFlags["minify"] {
+		cfg.Minify = minify
+	}
+
 	sourceFilepaths := flag.Args()
+	if len(sourceFilepaths) == 0 {
+		sourceFilepaths = cfg.Content
+	}
 
 	if len(sourceFilepaths) == 0 {
 		fmt.Println("please specify source files.")
 		os.Exit(1)
 	}
 
-	var validFilepaths []string
+	ignored, err := loadIgnoreList(ignoreFilename)
+	if err != nil {
+		fmt.Printf("%s.\n", err)
+		os.Exit(1)
+	}
 
-	for _, fp := range sourceFilepaths {
-		switch ext := filepath.Ext(fp); ext {
-		case ".js", ".html", ".gohtml":
-			validFilepaths = append(validFilepaths, fp)
-		default:
-			if len(ext) == 0 {
-				fmt.Println("file extensions are required.")
-			} else {
-				fmt.Printf("unrecognized extension %q.\n", ext)
-			}
-			os.Exit(1)
-		}
+	validFilepaths, err := resolveSources(sourceFilepaths, ignored)
+	if err != nil {
+		fmt.Printf("%s.\n", err)
+		os.Exit(1)
 	}
 
-	var lexerInput []string
+	opts := csskit.ScanOptions{Workers: jobs, Diagnostics: diagnose}
 
-	for _, fp := range validFilepaths {
-		file, err := os.Open(fp)
-		if err != nil {
-			var pathErr *fs.PathError
-			if errors.As(err, &pathErr) {
-				fmt.Printf("can't open file %q.\n", pathErr.Path)
-			} else {
-				fmt.Printf("unrecognized error: %s.\n", err)
+	if watchMode {
+		w := newWatcher(cfg, outFilepath, func() ([]string, error) {
+			return resolveSources(sourceFilepaths, ignored)
+		})
+		w.opts = opts
+		watch(w, watchInterval)
+	}
+
+	failed := false
+
+	if extractMode {
+		for _, fp := range validFilepaths {
+			err := walkFile(cfg, fp, func(lit extract.Literal) error {
+				printTokens(lit.Value)
+				return nil
+			})
+			if err != nil {
+				fmt.Printf("%s: %s.\n", fp, err)
+				failed = true
 			}
+		}
+		if failed {
 			os.Exit(1)
 		}
+		os.Exit(0)
+	}
+
+	safelist := scanSafelist(cfg, opts)
+	if safelist.Err != nil {
+		fmt.Printf("safelist: %s.\n", safelist.Err)
+		os.Exit(1)
+	}
 
-		var strs []string
+	var classes csskit.ClassSet
+	classes.Add(safelist.Classes...)
+	diags := safelist.Diagnostics
 
-		switch ext := filepath.Ext(fp); ext {
-		case ".js":
-			strs, err = extract.FromJS(file)
-		case ".html", ".gohtml":
-			strs, err = extract.FromHTML(file)
+	for _, res := range csskit.ScanFiles(cfg, validFilepaths, opts) {
+		if res.Err != nil {
+			fmt.Printf("%s: %s.\n", res.Path, fileError(res.Err))
+			failed = true
+			continue
 		}
+		classes.Add(res.Classes...)
+		diags = append(diags, res.Diagnostics...)
+	}
+
+	printDiagnostics(diags)
 
-		file.Close()
+	if failed || (strict && len(diags) > 0) {
+		os.Exit(1)
+	}
+
+	css, err := generateCSS(cfg, classes.Classes())
+	if err != nil {
+		fmt.Printf("%s: %s.\n", outFilepath, err)
+		os.Exit(1)
+	}
 
+	if checkMode {
+		current, err := os.ReadFile(outFilepath)
+		if errors.Is(err, fs.ErrNotExist) {
+			fmt.Printf("%s does not exist.\n", outFilepath)
+			os.Exit(1)
+		}
 		if err != nil {
-			fmt.Printf("unrecognized error: %s.\n", err)
+			fmt.Printf("%s: %s.\n", outFilepath, err)
+			os.Exit(1)
+		}
+		if diff := unifiedDiff(outFilepath, outFilepath+" (generated)", string(current), string(css)); diff != "" {
+			fmt.Printf("%s is out of date.\n", outFilepath)
+			fmt.Print(diff)
 			os.Exit(1)
 		}
+		os.Exit(0)
+	}
 
-		lexerInput = append(lexerInput, strs...)
+	if err := writeFileAtomic(outFilepath, css); err != nil {
+		fmt.Printf("%s: %s.\n", outFilepath, err)
+		os.Exit(1)
 	}
+}
 
-	if extractMode {
-		for _, str := range lexerInput {
-			fmt.Printf("%q -> ", str)
-
-			lex := csskit.NewLexer(str)
-			var tok csskit.Token
-
-			for tok.Type != csskit.TokenEOF {
-				tok = lex.NextToken()
-
-				switch tok.Type {
-				case csskit.TokenKeyword:
-					fmt.Printf("KEYWORD(%s) ", tok.Value)
-				case csskit.TokenNumber:
-					fmt.Printf("NUMBER(%s) ", tok.Value)
-				case csskit.TokenUnit:
-					fmt.Printf("UNIT(%s) ", tok.Value)
-				case csskit.TokenHyphen:
-					fmt.Printf("HYPHEN ")
-				case csskit.TokenSpace:
-					fmt.Printf("SPACE ")
-				case csskit.TokenGarbage:
-					fmt.Printf("GARBAGE ")
-				case csskit.TokenEOF:
-					fmt.Printf("EOF\n")
-				}
-			}
+func printTokens(str string) {
+	fmt.Printf("%q -> ", str)
+
+	lex := csskit.NewLexer(str)
+	var tok csskit.Token
+
+	for tok.Type != csskit.TokenEOF {
+		tok = lex.NextToken()
+
+		switch tok.Type {
+		case csskit.TokenKeyword:
+			fmt.Printf("KEYWORD(%s) ", tok.Value)
+		case csskit.TokenNumber:
+			fmt.Printf("NUMBER(%s) ", tok.Value)
+		case csskit.TokenUnit:
+			fmt.Printf("UNIT(%s) ", tok.Value)
+		case csskit.TokenHyphen:
+			fmt.Printf("HYPHEN ")
+		case csskit.TokenColon:
+			fmt.Printf("COLON ")
+		case csskit.TokenSpace:
+			fmt.Printf("SPACE ")
+		case csskit.TokenGarbage:
+			fmt.Printf("GARBAGE ")
+		case csskit.TokenEOF:
+			fmt.Printf("EOF\n")
 		}
-		os.Exit(0)
 	}
 }
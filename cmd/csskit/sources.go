@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const ignoreFilename = ".csskitignore"
+
+var defaultIgnored = []string{"node_modules", "vendor", ".git"}
+
+func isSupportedExt(ext string) bool {
+	switch ext {
+	case ".js", ".html", ".gohtml":
+		return true
+	default:
+		return false
+	}
+}
+
+// ignoreList holds the patterns of the paths skipped while walking the
+// sources. Patterns with a slash match paths relative to dir, the
+// directory of the ignore file, the others match base names.
+type ignoreList struct {
+	dir      string
+	patterns []string
+}
+
+func newIgnoreList(dir string, patterns ...string) *ignoreList {
+	return &ignoreList{dir: dir, patterns: patterns}
+}
+
+func loadIgnoreList(fp string) (*ignoreList, error) {
+	dir, err := filepath.Abs(filepath.Dir(fp))
+	if err != nil {
+		return nil, err
+	}
+	il := newIgnoreList(dir, defaultIgnored...)
+
+	file, err := os.Open(fp)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return il, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	sc := bufio.NewScanner(file)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSuffix(line, "/")
+		if _, err := path.Match(line, ""); err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern %q", fp, line)
+		}
+		il.patterns = append(il.patterns, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return il, nil
+}
+
+// relPath returns fp as a slash-separated path relative to il.dir,
+// and whether it lies within il.dir at all.
+func (il *ignoreList) relPath(fp string) (string, bool, error) {
+	abs, err := filepath.Abs(fp)
+	if err != nil {
+		return "", false, err
+	}
+	rel, err := filepath.Rel(il.dir, abs)
+	if err != nil {
+		// The path is on another volume.
+		return filepath.ToSlash(abs), false, nil
+	}
+	rel = filepath.ToSlash(rel)
+	inside := rel != ".." && !strings.HasPrefix(rel, "../")
+	return rel, inside, nil
+}
+
+// match reports whether rel, a path returned by relPath, is ignored.
+// Patterns with a slash don't apply to paths outside of il.dir.
+func (il *ignoreList) match(rel string, inside bool) bool {
+	base := path.Base(rel)
+	for _, pattern := range il.patterns {
+		if strings.Contains(pattern, "/") {
+			if inside && matchGlob(strings.TrimPrefix(pattern, "/"), rel) {
+				return true
+			}
+		} else if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRoot is like match for the root of a walk, which is also ignored
+// if any of its parent directories within il.dir is.
+func (il *ignoreList) matchRoot(rel string, inside bool) bool {
+	if !inside {
+		return il.match(rel, false)
+	}
+	for ; rel != "."; rel = path.Dir(rel) {
+		if il.match(rel, true) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasGlobMeta(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// matchGlob reports whether name matches the slash-separated pattern.
+// In addition to path.Match syntax, a "**" segment matches zero or more
+// path segments.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+	return len(name) == 0
+}
+
+// splitGlob splits a glob into the longest leading directory without
+// glob metacharacters and the remaining pattern.
+func splitGlob(glob string) (string, string) {
+	segments := strings.Split(filepath.ToSlash(glob), "/")
+	i := 0
+	for i < len(segments)-1 && !hasGlobMeta(segments[i]) {
+		i++
+	}
+	root := strings.Join(segments[:i], "/")
+	if root == "" {
+		if strings.HasPrefix(glob, "/") {
+			root = "/"
+		} else {
+			root = "."
+		}
+	}
+	return filepath.FromSlash(root), strings.Join(segments[i:], "/")
+}
+
+// resolveSources expands directories and globs into the list of source
+// files to scan. Explicitly named files must have a supported extension,
+// files found while walking are skipped if they don't.
+func resolveSources(args []string, il *ignoreList) ([]string, error) {
+	var files []string
+	seen := make(map[string]struct{})
+
+	add := func(fp string) {
+		fp = filepath.Clean(fp)
+		if _, ok := seen[fp]; ok {
+			return
+		}
+		seen[fp] = struct{}{}
+		files = append(files, fp)
+	}
+
+	for _, arg := range args {
+		if hasGlobMeta(arg) {
+			root, pattern := splitGlob(arg)
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid glob %q", arg)
+			}
+			err := walkSources(root, il, func(fp, rel string) {
+				if matchGlob(pattern, rel) {
+					add(fp)
+				}
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		info, err := os.Stat(arg)
+		if err != nil {
+			var pathErr *fs.PathError
+			if errors.As(err, &pathErr) {
+				return nil, fmt.Errorf("can't open file %q", pathErr.Path)
+			}
+			return nil, err
+		}
+
+		if info.IsDir() {
+			err := walkSources(arg, il, func(fp, _ string) {
+				add(fp)
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		switch ext := filepath.Ext(arg); {
+		case isSupportedExt(ext):
+			add(arg)
+		case len(ext) == 0:
+			return nil, errors.New("file extensions are required")
+		default:
+			return nil, fmt.Errorf("unrecognized extension %q", ext)
+		}
+	}
+
+	return files, nil
+}
+
+// walkSources calls fn for the supported files under root, along with
+// their slash-separated paths relative to root. Nothing is walked if
+// root itself is ignored.
+func walkSources(root string, il *ignoreList, fn func(fp, rel string)) error {
+	rootRel, inside, err := il.relPath(root)
+	if err != nil {
+		return err
+	}
+	if il.matchRoot(rootRel, inside) {
+		return nil
+	}
+	return filepath.WalkDir(root, func(fp string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if fp == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, fp)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if il.match(path.Join(rootRel, rel), inside) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !isSupportedExt(filepath.Ext(fp)) {
+			return nil
+		}
+		fn(fp, rel)
+		return nil
+	})
+}
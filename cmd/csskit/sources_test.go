@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeTree creates empty files at the slash-separated paths under dir.
+func writeTree(t *testing.T, dir string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		fp := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(fp), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fp, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResolveSourcesIgnored(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir,
+		"web/index.html",
+		"web/gen/out.html",
+		"web/app.js",
+		"web/app.min.js",
+		"vendor/lib/a.js",
+		"node_modules/pkg/b.js",
+		"other/gen/c.html",
+		"templates/page.gohtml",
+	)
+	ignore := "# generated\nweb/gen/\n*.min.js\n"
+	if err := os.WriteFile(filepath.Join(dir, ignoreFilename), []byte(ignore), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	il, err := loadIgnoreList(filepath.Join(dir, ignoreFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"whole tree", []string{"."}, []string{
+			"other/gen/c.html", "templates/page.gohtml", "web/app.js", "web/index.html",
+		}},
+		{"slash pattern from a nested root", []string{"web"}, []string{"web/app.js", "web/index.html"}},
+		{"ignored root", []string{"vendor"}, nil},
+		{"root within an ignored directory", []string{"node_modules/pkg", "web/gen"}, nil},
+		{"glob", []string{"**/*.html"}, []string{"other/gen/c.html", "web/index.html"}},
+		{"glob within an ignored directory", []string{"vendor/**/*.js"}, nil},
+		{"explicit file", []string{"web/gen/out.html"}, []string{"web/gen/out.html"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := make([]string, len(tt.args))
+			for i, arg := range tt.args {
+				args[i] = filepath.Join(dir, filepath.FromSlash(arg))
+			}
+			files, err := resolveSources(args, il)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, fp := range files {
+				rel, err := filepath.Rel(dir, fp)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, filepath.ToSlash(rel))
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnoreListOutsideDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, "site/vendor/a.js", "site/gen/b.js", "site/c.js")
+	il := newIgnoreList(filepath.Join(dir, "project"), append(defaultIgnored, "site/gen", "c.js")...)
+
+	files, err := resolveSources([]string{filepath.Join(dir, "site")}, il)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the patterns without a slash apply outside of the directory
+	// of the ignore file.
+	want := []string{filepath.Join(dir, "site", "gen", "b.js")}
+	if !slices.Equal(files, want) {
+		t.Errorf("got %q, want %q", files, want)
+	}
+}
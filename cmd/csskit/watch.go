@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/igormichalak/csskit"
+)
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+	classes []csskit.RawCSSClass
+	diags   []csskit.FileDiagnostic
+	err     error
+}
+
+type watcher struct {
+	config      *csskit.Config
+	outFilepath string
+	opts        csskit.ScanOptions
+	sources     func() ([]string, error)
+	files       map[string]*fileState
+	order       []string
+	lastCSS     []byte
+}
+
+func newWatcher(cfg *csskit.Config, outFilepath string, sources func() ([]string, error)) *watcher {
+	w := &watcher{
+		config:      cfg,
+		outFilepath: outFilepath,
+		sources:     sources,
+		files:       make(map[string]*fileState),
+	}
+	if data, err := os.ReadFile(outFilepath); err == nil {
+		w.lastCSS = data
+	}
+	return w
+}
+
+// update re-extracts and re-parses every source file whose size or
+// modification time changed since the previous call. It reports whether
+// the set of classes may have changed.
+func (w *watcher) update() (bool, error) {
+	paths, err := w.sources()
+	if err != nil {
+		return false, err
+	}
+
+	changed := len(paths) != len(w.order)
+	seen := make(map[string]struct{}, len(paths))
+	var stale []string
+	var infos []fs.FileInfo
+
+	for i, fp := range paths {
+		seen[fp] = struct{}{}
+		if !changed && w.order[i] != fp {
+			changed = true
+		}
+
+		info, err := os.Stat(fp)
+		if err != nil {
+			var pathErr *fs.PathError
+			if errors.As(err, &pathErr) {
+				err = fmt.Errorf("can't stat file %q", pathErr.Path)
+			}
+			if state, ok := w.files[fp]; ok && state.modTime.IsZero() {
+				continue
+			}
+			w.files[fp] = &fileState{err: err}
+			changed = true
+			continue
+		}
+
+		state, ok := w.files[fp]
+		if ok && state.modTime.Equal(info.ModTime()) && state.size == info.Size() {
+			continue
+		}
+
+		stale = append(stale, fp)
+		infos = append(infos, info)
+	}
+
+	for i, res := range csskit.ScanFiles(w.config, stale, w.opts) {
+		state := &fileState{
+			modTime: infos[i].ModTime(),
+			size:    infos[i].Size(),
+			classes: res.Classes,
+			diags:   res.Diagnostics,
+			err:     fileError(res.Err),
+		}
+		printDiagnostics(state.diags)
+		w.files[res.Path] = state
+		changed = true
+	}
+
+	for fp := range w.files {
+		if _, ok := seen[fp]; !ok {
+			delete(w.files, fp)
+			changed = true
+		}
+	}
+
+	w.order = paths
+	return changed, nil
+}
+
+// rebuild regenerates the stylesheet from the cached classes and writes it
+// only if the output differs from the last written version.
+func (w *watcher) rebuild() (bool, error) {
+	safelist := scanSafelist(w.config, csskit.ScanOptions{})
+	if safelist.Err != nil {
+		return false, fmt.Errorf("safelist: %w", safelist.Err)
+	}
+
+	var classes csskit.ClassSet
+	classes.Add(safelist.Classes...)
+
+	var errs []error
+
+	for _, fp := range w.order {
+		state := w.files[fp]
+		if state.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", fp, state.err))
+			continue
+		}
+		classes.Add(state.classes...)
+	}
+
+	if len(errs) > 0 {
+		return false, errors.Join(errs...)
+	}
+
+	css, err := generateCSS(w.config, classes.Classes())
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(css, w.lastCSS) {
+		return false, nil
+	}
+	if err := writeFileAtomic(w.outFilepath, css); err != nil {
+		return false, fmt.Errorf("%s: %w", w.outFilepath, err)
+	}
+	w.lastCSS = css
+	return true, nil
+}
+
+func watch(w *watcher, interval time.Duration) {
+	for {
+		changed, err := w.update()
+		if err != nil {
+			printErrors(err)
+		} else if changed {
+			written, err := w.rebuild()
+			if err != nil {
+				printErrors(err)
+			} else if written {
+				fmt.Printf("%s: written at %s.\n", w.outFilepath, time.Now().Format(time.TimeOnly))
+			}
+		}
+		time.Sleep(interval)
+	}
+}
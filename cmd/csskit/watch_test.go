@@ -0,0 +1,151 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/igormichalak/csskit"
+)
+
+func writeSource(t *testing.T, fp, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(fp, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// The modification time is set explicitly, as successive writes may
+	// fall within the resolution of the file system's timestamps.
+	if err := os.Chtimes(fp, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func modTime(t *testing.T, fp string) time.Time {
+	t.Helper()
+	info, err := os.Stat(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.ModTime()
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.html")
+	b := filepath.Join(dir, "b.html")
+	out := filepath.Join(dir, "out.css")
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	writeSource(t, a, `<p class="w-4">`, start)
+	writeSource(t, b, `<p class="m-2">`, start)
+
+	sources := []string{a, b}
+	w := newWatcher(csskit.DefaultConfig(), out, func() ([]string, error) {
+		return sources, nil
+	})
+
+	step := func(wantChanged, wantWritten bool) {
+		t.Helper()
+		changed, err := w.update()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed != wantChanged {
+			t.Fatalf("update() = %v, want %v", changed, wantChanged)
+		}
+		if !changed {
+			return
+		}
+		written, err := w.rebuild()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written != wantWritten {
+			t.Fatalf("rebuild() = %v, want %v", written, wantWritten)
+		}
+	}
+
+	step(true, true)
+	css, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(css), ".w-4 ") || !strings.Contains(string(css), ".m-2 ") {
+		t.Fatalf("missing classes in:\n%s", css)
+	}
+	if err := os.Chtimes(out, start, start); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing changed.
+	step(false, false)
+
+	// The content of a changes, but not its classes.
+	stateA, stateB := w.files[a], w.files[b]
+	writeSource(t, a, `<p id="x" class="w-4">`, start.Add(time.Minute))
+	step(true, false)
+	if w.files[a] == stateA {
+		t.Error("the changed file wasn't scanned again")
+	}
+	if w.files[b] != stateB {
+		t.Error("the unchanged file was scanned again")
+	}
+	if got := modTime(t, out); !got.Equal(start) {
+		t.Errorf("the output was rewritten at %v", got)
+	}
+
+	// b is removed.
+	sources = []string{a}
+	if err := os.Remove(b); err != nil {
+		t.Fatal(err)
+	}
+	step(true, true)
+	if _, ok := w.files[b]; ok {
+		t.Error("the removed file is still cached")
+	}
+	css, err = os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(css), ".w-4 ") || strings.Contains(string(css), ".m-2 ") {
+		t.Errorf("got:\n%s\nwant only w-4", css)
+	}
+}
+
+func TestWatcherKeepsExistingOutput(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.html")
+	out := filepath.Join(dir, "out.css")
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	writeSource(t, a, `<p class="w-4">`, start)
+
+	css, err := generateCSS(csskit.DefaultConfig(), mustParse(t, "w-4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeSource(t, out, string(css), start)
+
+	w := newWatcher(csskit.DefaultConfig(), out, func() ([]string, error) {
+		return []string{a}, nil
+	})
+	if _, err := w.update(); err != nil {
+		t.Fatal(err)
+	}
+	written, err := w.rebuild()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written || !modTime(t, out).Equal(start) {
+		t.Error("an up-to-date output was rewritten on start")
+	}
+}
+
+func mustParse(t *testing.T, input string) []csskit.RawCSSClass {
+	t.Helper()
+	rcs, err := csskit.NewParser(csskit.NewLexer(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rcs
+}
@@ -1,8 +1,11 @@
 package csskit
 
 import (
+	"errors"
 	"fmt"
 	"image/color"
+	"maps"
+	"slices"
 )
 
 const (
@@ -39,24 +42,22 @@ var AllColorNames = []string{
 	ColorPink, ColorRose,
 }
 
-var shadeCount int
-
-func init() {
-	shadeCount = len(Shades)
-}
-
 var Shades = []int{50, 100, 200, 300, 400, 500, 600, 700, 800, 900, 950}
 
-func getClosestShades(num int) (int, int) {
-	if num <= Shades[0] {
+func getClosestShades(shades []int, num float64) (int, int) {
+	shadeCount := len(shades)
+	if shadeCount == 0 {
 		return -1, -1
 	}
-	if num >= Shades[shadeCount-1] {
+	if num < float64(shades[0]) || num > float64(shades[shadeCount-1]) {
 		return -1, -1
 	}
+	if shadeCount == 1 {
+		return shades[0], shades[0]
+	}
 	for i := 0; i < shadeCount-1; i++ {
-		if num >= Shades[i] && num <= Shades[i+1] {
-			return Shades[i], Shades[i+1]
+		if num >= float64(shades[i]) && num <= float64(shades[i+1]) {
+			return shades[i], shades[i+1]
 		}
 	}
 	return -1, -1
@@ -71,19 +72,31 @@ func interpolateNRGBA(a, b color.NRGBA, t float64) color.NRGBA {
 	}
 }
 
-func getColor(name string, shade float64) color.NRGBA {
-	prevShade, nextShade := getClosestShades(int(shade))
+// getColor returns the color of the given shade, interpolating
+// between the two closest shades defined in shadeMap.
+func getColor(shadeMap map[int]color.NRGBA, shade float64) (color.NRGBA, error) {
+	shades := slices.Sorted(maps.Keys(shadeMap))
+	prevShade, nextShade := getClosestShades(shades, shade)
 	if prevShade == -1 || nextShade == -1 {
-		panic(fmt.Errorf("color shade out of bounds (50-950): %f", shade))
-	}
-	shadeMap, ok := Colors[name]
-	if !ok {
-		panic(fmt.Errorf("unknown color name: %s", name))
+		if len(shades) == 0 {
+			return color.NRGBA{}, errors.New("color has no shades")
+		}
+		return color.NRGBA{}, fmt.Errorf("color shade out of bounds (%d-%d): %v", shades[0], shades[len(shades)-1], shade)
 	}
 	prevColor := shadeMap[prevShade]
 	nextColor := shadeMap[nextShade]
-	mixf := (shade - float64(prevShade)) / float64(nextShade - prevShade)
-	return interpolateNRGBA(prevColor, nextColor, mixf)
+	if prevShade == nextShade {
+		return prevColor, nil
+	}
+	mixf := (shade - float64(prevShade)) / float64(nextShade-prevShade)
+	return interpolateNRGBA(prevColor, nextColor, mixf), nil
+}
+
+func formatColor(c color.NRGBA) string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
 }
 
 var Colors = map[string]map[int]color.NRGBA{
@@ -0,0 +1,331 @@
+package csskit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/igormichalak/csskit/extract"
+)
+
+const DefaultConfigFilename = "csskit.json"
+
+const (
+	// DarkModeMedia emits dark mode rules inside
+	// a prefers-color-scheme media query.
+	DarkModeMedia = "media"
+	// DarkModeClass applies dark mode rules to descendants
+	// of an element with the "dark" class.
+	DarkModeClass = "class"
+)
+
+type Breakpoint struct {
+	Name     string `json:"name"`
+	MinWidth string `json:"minWidth"`
+}
+
+var DefaultBreakpoints = []Breakpoint{
+	{Name: "sm", MinWidth: "640px"},
+	{Name: "md", MinWidth: "768px"},
+	{Name: "lg", MinWidth: "1024px"},
+	{Name: "xl", MinWidth: "1280px"},
+}
+
+// ClassAttribute is an HTML attribute that holds classes, see
+// the extract.Syntax constants for the syntaxes of its value.
+type ClassAttribute struct {
+	Name   string `json:"name"`
+	Syntax string `json:"syntax"`
+}
+
+type Config struct {
+	// Content lists the source files, directories and globs to scan.
+	// LoadConfig resolves relative paths against the config file's
+	// directory, as it does with Output.
+	Content []string `json:"content"`
+	// Output is the path of the generated stylesheet.
+	Output string `json:"output"`
+	// RemBase is the number of unitless steps that make up 1rem,
+	// e.g. with the default of 4 "w-4" is 1rem wide.
+	RemBase float64 `json:"remBase"`
+	// Patterns lists the enabled pattern groups, all groups are
+	// enabled if it's empty.
+	Patterns []string `json:"patterns"`
+	// Colors adds custom colors to the palette, shades are given
+	// as "#rgb", "#rrggbb" or "#rrggbbaa" hex strings. Shades that
+	// are not defined are interpolated from the closest defined ones.
+	// They take effect once the config is validated.
+	Colors map[string]map[int]string `json:"colors"`
+	// Breakpoints are ordered from the smallest to the largest.
+	Breakpoints []Breakpoint `json:"breakpoints"`
+	// Safelist lists classes that are always generated.
+	Safelist []string `json:"safelist"`
+	// IgnoreUnknown lists the patterns of classes that are not
+	// reported as unknown utilities, e.g. "col-*", in the syntax
+	// of path.Match. Classes with variants are also matched
+	// without them.
+	IgnoreUnknown []string `json:"ignoreUnknown"`
+	// DarkMode selects how the "dark:" variant is emitted,
+	// either DarkModeMedia or DarkModeClass.
+	DarkMode string `json:"darkMode"`
+	// Minify makes the generated stylesheet as compact as possible.
+	Minify bool `json:"minify"`
+	// TemplateDelims are the left and right action delimiters
+	// of Go templates, "{{" and "}}" are used if it's empty.
+	TemplateDelims []string `json:"templateDelims"`
+	// ClassAttributes lists the HTML attributes that hold classes
+	// in addition to the class attribute.
+	ClassAttributes []ClassAttribute `json:"classAttributes"`
+	// Utilities declares additional class patterns, they're
+	// registered on top of Registry once the config is validated.
+	Utilities []PatternDef `json:"utilities"`
+	// Registry holds the class patterns, the built-in
+	// patterns are used if it's nil.
+	Registry *Registry `json:"-"`
+
+	palette  map[string]map[int]color.NRGBA
+	compiled *Registry
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Output:      "output.css",
+		RemBase:     4,
+		Breakpoints: slices.Clone(DefaultBreakpoints),
+		DarkMode:    DarkModeMedia,
+	}
+}
+
+var defaultConfig = DefaultConfig()
+
+func LoadConfig(fp string) (*Config, error) {
+	data, err := os.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := DefaultConfig()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("%s: %v", fp, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", fp, err)
+	}
+
+	dir := filepath.Dir(fp)
+	for i, p := range cfg.Content {
+		cfg.Content[i] = resolvePath(dir, p)
+	}
+	cfg.Output = resolvePath(dir, cfg.Output)
+	return cfg, nil
+}
+
+// resolvePath makes a relative path p relative to dir instead.
+func resolvePath(dir, p string) string {
+	if dir == "." || p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
+
+func (c *Config) Validate() error {
+	if c.RemBase <= 0 {
+		return fmt.Errorf("remBase must be positive: %v", c.RemBase)
+	}
+
+	c.compiled = nil
+	if len(c.Utilities) > 0 {
+		base := c.Registry
+		if base == nil {
+			base = defaultRegistry
+		}
+		reg := base.clone()
+		for i, def := range c.Utilities {
+			cp, err := def.Pattern()
+			if err == nil {
+				err = reg.Register(cp)
+			}
+			if err != nil {
+				return fmt.Errorf("utilities[%d]: %v", i, err)
+			}
+		}
+		c.compiled = reg
+	}
+
+	for _, group := range c.Patterns {
+		if !c.registry().hasGroup(group) {
+			return fmt.Errorf("unknown pattern group: %q", group)
+		}
+	}
+
+	palette := make(map[string]map[int]color.NRGBA, len(c.Colors))
+	for name, shades := range c.Colors {
+		if !isKeyword(name) {
+			return fmt.Errorf("invalid color name: %q", name)
+		}
+		if len(shades) == 0 {
+			return fmt.Errorf("color %q has no shades", name)
+		}
+		shadeMap := make(map[int]color.NRGBA, len(shades))
+		for shade, hex := range shades {
+			if !slices.Contains(Shades, shade) {
+				return fmt.Errorf("invalid shade for color %q: %d", name, shade)
+			}
+			col, err := parseHexColor(hex)
+			if err != nil {
+				return fmt.Errorf("invalid color %s-%d: %v", name, shade, err)
+			}
+			shadeMap[shade] = col
+		}
+		palette[name] = shadeMap
+	}
+	c.palette = palette
+
+	for i, bp := range c.Breakpoints {
+		if !isKeyword(bp.Name) {
+			return fmt.Errorf("invalid breakpoint name: %q", bp.Name)
+		}
+		if c.breakpointIndex(bp.Name) != i {
+			return fmt.Errorf("duplicate breakpoint: %q", bp.Name)
+		}
+		if stateIndex(bp.Name) != -1 || bp.Name == DarkVariant {
+			return fmt.Errorf("breakpoint name conflicts with a state variant: %q", bp.Name)
+		}
+		if bp.MinWidth == "" {
+			return fmt.Errorf("missing minWidth for breakpoint %q", bp.Name)
+		}
+	}
+
+	switch c.DarkMode {
+	case DarkModeMedia, DarkModeClass:
+	default:
+		return fmt.Errorf("invalid darkMode: %q", c.DarkMode)
+	}
+
+	if len(c.TemplateDelims) > 0 {
+		if len(c.TemplateDelims) != 2 || c.TemplateDelims[0] == "" || c.TemplateDelims[1] == "" {
+			return fmt.Errorf("templateDelims must hold the left and right delimiters: %q", c.TemplateDelims)
+		}
+	}
+
+	for _, pattern := range c.IgnoreUnknown {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid ignoreUnknown pattern: %q", pattern)
+		}
+	}
+
+	for i, attr := range c.ClassAttributes {
+		if attr.Name == "" || strings.ContainsAny(attr.Name, " \t\n\f\r/>=\"'") {
+			return fmt.Errorf("classAttributes[%d]: invalid name: %q", i, attr.Name)
+		}
+		if !extract.ValidAttrSyntax(attr.Syntax) {
+			return fmt.Errorf("classAttributes[%d]: unknown syntax: %q", i, attr.Syntax)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) registry() *Registry {
+	if c.compiled != nil {
+		return c.compiled
+	}
+	if c.Registry == nil {
+		return defaultRegistry
+	}
+	return c.Registry
+}
+
+func (c *Config) shadeMap(name string) (map[int]color.NRGBA, bool) {
+	if shadeMap, ok := c.palette[name]; ok {
+		return shadeMap, true
+	}
+	shadeMap, ok := Colors[name]
+	return shadeMap, ok
+}
+
+// colorNames returns the built-in color names
+// followed by the sorted custom color names.
+func (c *Config) colorNames() []string {
+	names := slices.Clone(AllColorNames)
+	for _, name := range slices.Sorted(maps.Keys(c.palette)) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (c *Config) breakpointIndex(name string) int {
+	return slices.IndexFunc(c.Breakpoints, func(bp Breakpoint) bool {
+		return bp.Name == name
+	})
+}
+
+func (c *Config) ignoredUnknown(class string) bool {
+	base := class[strings.LastIndexByte(class, ':')+1:]
+	for _, pattern := range c.IgnoreUnknown {
+		if ok, _ := path.Match(pattern, class); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) groupEnabled(group string) bool {
+	return len(c.Patterns) == 0 || slices.Contains(c.Patterns, group)
+}
+
+func isKeyword(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, c := range s {
+		if !isLowerLetter(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseHexColor(s string) (color.NRGBA, error) {
+	if len(s) == 0 || s[0] != '#' {
+		return color.NRGBA{}, errors.New("missing '#' prefix")
+	}
+	s = s[1:]
+
+	switch len(s) {
+	case 3:
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]}) + "ff"
+	case 6:
+		s += "ff"
+	case 8:
+	default:
+		return color.NRGBA{}, fmt.Errorf("invalid length: %d", len(s))
+	}
+
+	n, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, err
+	}
+	return color.NRGBA{
+		R: uint8(n >> 24),
+		G: uint8(n >> 16),
+		B: uint8(n >> 8),
+		A: uint8(n),
+	}, nil
+}
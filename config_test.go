@@ -0,0 +1,97 @@
+package csskit
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/igormichalak/csskit/extract"
+)
+
+func TestLoadConfigResolvesPaths(t *testing.T) {
+	dir := t.TempDir()
+	abs := filepath.Join(dir, "abs.html")
+	data := `{"content": ["./web", "templates/**/*.gohtml", "../shared", "` + filepath.ToSlash(abs) + `"], "output": "static/out.css"}`
+	fp := filepath.Join(dir, "conf", DefaultConfigFilename)
+	if err := os.MkdirAll(filepath.Dir(fp), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fp, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantContent := []string{
+		filepath.Join(dir, "conf", "web"),
+		filepath.Join(dir, "conf", "templates", "**", "*.gohtml"),
+		filepath.Join(dir, "shared"),
+		abs,
+	}
+	if !slices.Equal(cfg.Content, wantContent) {
+		t.Errorf("Content = %q, want %q", cfg.Content, wantContent)
+	}
+	if want := filepath.Join(dir, "conf", "static", "out.css"); cfg.Output != want {
+		t.Errorf("Output = %q, want %q", cfg.Output, want)
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	tests := []struct {
+		dir, path, want string
+	}{
+		{".", "./web", "./web"},
+		{"conf", "./web", filepath.Join("conf", "web")},
+		{"conf", "../web", "web"},
+		{"conf", "", ""},
+	}
+	for _, tt := range tests {
+		if got := resolvePath(tt.dir, tt.path); got != tt.want {
+			t.Errorf("resolvePath(%q, %q) = %q, want %q", tt.dir, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestValidateClassAttributes(t *testing.T) {
+	tests := []struct {
+		attr ClassAttribute
+		ok   bool
+	}{
+		{ClassAttribute{Name: "x-bind:class", Syntax: "js"}, true},
+		{ClassAttribute{Name: "hx-classes", Syntax: "htmx"}, true},
+		{ClassAttribute{Name: "data-class"}, true},
+		{ClassAttribute{Name: ""}, false},
+		{ClassAttribute{Name: "data class"}, false},
+		{ClassAttribute{Name: "a=b"}, false},
+		{ClassAttribute{Name: `a"`}, false},
+		{ClassAttribute{Name: "a/"}, false},
+		{ClassAttribute{Name: "data-class", Syntax: "css"}, false},
+		{ClassAttribute{Name: "data-class", Syntax: "JS"}, false},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.ClassAttributes = []ClassAttribute{tt.attr}
+		if err := cfg.Validate(); (err == nil) != tt.ok {
+			t.Errorf("Validate() with %+v: error = %v", tt.attr, err)
+		}
+	}
+}
+
+func TestExtractOptions(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TemplateDelims = []string{"[[", "]]"}
+	cfg.ClassAttributes = []ClassAttribute{{Name: ":class", Syntax: "js"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	opts := cfg.ExtractOptions()
+	if opts.TemplateDelims != (extract.Delims{Left: "[[", Right: "]]"}) {
+		t.Errorf("TemplateDelims = %+v", opts.TemplateDelims)
+	}
+	if want := []extract.ClassAttr{{Name: ":class", Syntax: extract.SyntaxJS}}; !slices.Equal(opts.ClassAttrs, want) {
+		t.Errorf("ClassAttrs = %+v, want %+v", opts.ClassAttrs, want)
+	}
+}
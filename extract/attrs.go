@@ -0,0 +1,62 @@
+package extract
+
+import (
+	"slices"
+	"strings"
+)
+
+// The syntaxes of the values of class-bearing attributes.
+const (
+	// SyntaxClassList is a whitespace-separated list of classes,
+	// like the value of the class attribute.
+	SyntaxClassList = ""
+	// SyntaxJS is a JavaScript expression, such as the value of Alpine's
+	// x-bind:class, the classes are read from its string literals.
+	SyntaxJS = "js"
+	// SyntaxHTMX is the value of htmx's classes attribute, e.g.
+	// "add w-4:1s, remove p-2 & toggle hidden".
+	SyntaxHTMX = "htmx"
+)
+
+var attrSyntaxes = []string{SyntaxClassList, SyntaxJS, SyntaxHTMX}
+
+// ClassAttr is an HTML attribute that holds classes.
+type ClassAttr struct {
+	Name   string
+	Syntax string
+}
+
+// ValidAttrSyntax reports whether syntax is one of the known syntaxes.
+func ValidAttrSyntax(syntax string) bool {
+	return slices.Contains(attrSyntaxes, syntax)
+}
+
+// classAttrSyntax reports whether the attribute with the given lowercase
+// name holds classes, and the syntax of its value. The class attribute
+// is a class list, unless it's listed in ClassAttrs.
+func (opts Options) classAttrSyntax(name string) (string, bool) {
+	for _, attr := range opts.ClassAttrs {
+		if strings.EqualFold(attr.Name, name) {
+			return attr.Syntax, true
+		}
+	}
+	return SyntaxClassList, name == "class"
+}
+
+// htmxClassList turns an htmx classes value into a class list by blanking
+// out the separators and the delays, which keeps the offsets intact.
+func htmxClassList(value string) string {
+	b := []byte(value)
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == ',' || b[i] == '&':
+			b[i] = ' '
+		case b[i] == ':' && i+1 < len(b) && '0' <= b[i+1] && b[i+1] <= '9':
+			for ; i < len(b) && !isHTMLSpace(b[i]) && b[i] != ',' && b[i] != '&'; i++ {
+				b[i] = ' '
+			}
+			i--
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,105 @@
+package extract
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func htmlLiteralsWithOptions(input string, opts Options) ([]Literal, error) {
+	return collectLiterals(strings.NewReader(input), func(rd io.Reader, fn func(Literal) error) error {
+		return WalkHTMLWithOptions(rd, opts, fn)
+	})
+}
+
+func TestHTMXClassList(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"add w-4", "add w-4"},
+		{"add w-4:1s", "add w-4   "},
+		{"add w-4:1s, remove p-2", "add w-4     remove p-2"},
+		{"add w-4:500ms & toggle p-2:1s", "add w-4         toggle p-2   "},
+		{"toggle md:w-4:1s,add hover:p-2", "toggle md:w-4    add hover:p-2"},
+		{"add w-4,remove p-2&toggle m-1", "add w-4 remove p-2 toggle m-1"},
+	}
+	for _, tt := range tests {
+		got := htmxClassList(tt.value)
+		if got != tt.want {
+			t.Errorf("htmxClassList(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+		if len(got) != len(tt.value) {
+			t.Errorf("htmxClassList(%q) changed the length to %d", tt.value, len(got))
+		}
+	}
+}
+
+func TestClassAttrs(t *testing.T) {
+	opts := Options{ClassAttrs: []ClassAttr{
+		{Name: ":class", Syntax: SyntaxJS},
+		{Name: "x-bind:class", Syntax: SyntaxJS},
+		{Name: "hx-classes", Syntax: SyntaxHTMX},
+		{Name: "Data-Classes"},
+	}}
+	tests := []struct {
+		name  string
+		input string
+		want  []Literal
+	}{
+		{
+			"js shorthand",
+			`<p :class="{ 'w-4': on }">`,
+			[]Literal{{Value: "w-4", Pos: Position{Offset: 14, Line: 1, Column: 15}, Kind: KindString}},
+		},
+		{
+			"js on a later line",
+			"<div\n  class=\"m-2\"\n  x-bind:class=\"on ? `w-4` : 'w-8'\">",
+			[]Literal{
+				{Value: "m-2", Pos: Position{Offset: 14, Line: 2, Column: 10}},
+				{Value: "w-4", Pos: Position{Offset: 41, Line: 3, Column: 23}, Kind: KindString},
+				{Value: "w-8", Pos: Position{Offset: 49, Line: 3, Column: 31}, Kind: KindString},
+			},
+		},
+		{
+			"htmx",
+			`<p hx-classes="add w-4:1s, remove p-2">`,
+			[]Literal{{Value: "add w-4     remove p-2", Pos: Position{Offset: 15, Line: 1, Column: 16}}},
+		},
+		{
+			"case-insensitive name",
+			`<p DATA-classes="w-4" data-class="w-8">`,
+			[]Literal{{Value: "w-4", Pos: Position{Offset: 17, Line: 1, Column: 18}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := htmlLiteralsWithOptions(tt.input, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassAttrsOverrideClass(t *testing.T) {
+	opts := Options{ClassAttrs: []ClassAttr{{Name: "CLASS", Syntax: SyntaxJS}}}
+	lits, err := htmlLiteralsWithOptions(`<p class="on ? 'w-4' : 'w-8'">`, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"w-4", "w-8"}
+	if got := literalValues(lits); !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestClassAttrsJSError(t *testing.T) {
+	opts := Options{ClassAttrs: []ClassAttr{{Name: ":class", Syntax: SyntaxJS}}}
+	if _, err := htmlLiteralsWithOptions(`<p :class="'w-4">`, opts); err != ErrUnterminatedString {
+		t.Errorf("error = %v, want %v", err, ErrUnterminatedString)
+	}
+}
@@ -0,0 +1,69 @@
+package extract
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LiteralsFromFile extracts literals from the file at fp, choosing the
+// extractor by the file extension. The positions of the returned literals
+// carry fp as their filename.
+func LiteralsFromFile(fp string) ([]Literal, error) {
+	var acc []Literal
+	err := WalkFile(fp, func(lit Literal) error {
+		acc = append(acc, lit)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// Options configure the extraction of literals from files.
+type Options struct {
+	// TemplateDelims are the action delimiters used in .gohtml files,
+	// DefaultDelims are used if they are not set.
+	TemplateDelims Delims
+	// ClassAttrs are the HTML attributes that hold classes in addition
+	// to the class attribute.
+	ClassAttrs []ClassAttr
+}
+
+// WalkFile is like LiteralsFromFile, but calls fn for every literal
+// as soon as it is read instead of collecting them.
+func WalkFile(fp string, fn func(lit Literal) error) error {
+	return WalkFileWithOptions(fp, Options{}, fn)
+}
+
+func WalkFileWithOptions(fp string, opts Options, fn func(lit Literal) error) error {
+	var walkFn func(rd io.Reader, fn func(lit Literal) error) error
+
+	switch ext := filepath.Ext(fp); ext {
+	case ".js":
+		walkFn = WalkJS
+	case ".html":
+		walkFn = func(rd io.Reader, fn func(lit Literal) error) error {
+			return WalkHTMLWithOptions(rd, opts, fn)
+		}
+	case ".gohtml":
+		walkFn = func(rd io.Reader, fn func(lit Literal) error) error {
+			return WalkGoHTML(rd, opts, fn)
+		}
+	default:
+		return fmt.Errorf("unrecognized extension %q", ext)
+	}
+
+	file, err := os.Open(fp)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return walkFn(file, func(lit Literal) error {
+		lit.Pos.Filename = fp
+		return fn(lit)
+	})
+}
@@ -3,50 +3,354 @@ package extract
 import (
 	"bufio"
 	"errors"
+	"fmt"
 	"io"
+	"slices"
 	"strings"
+	"unicode/utf8"
 )
 
-var classAttrStart = []rune("class=\"")
-
 func FromHTML(rd io.Reader) ([]string, error) {
-	pit := newPeekIterator(bufio.NewReader(rd))
+	lits, err := LiteralsFromHTML(rd)
+	if err != nil {
+		return nil, err
+	}
+	return literalValues(lits), nil
+}
 
-	const (
-		stateClassAttr = iota
-		stateOther
-	)
+func LiteralsFromHTML(rd io.Reader) ([]Literal, error) {
+	return collectLiterals(rd, WalkHTML)
+}
 
-	state := stateOther
+// WalkHTML calls fn for the value of every class attribute in rd as soon
+// as it is read. Walking stops at the first error returned by fn.
+//
+// Comments and the contents of style elements are skipped. The contents
+// of script elements are scanned like JavaScript files, unless the script
+// holds data (e.g. JSON), or like HTML if it holds a template.
+func WalkHTML(rd io.Reader, fn func(lit Literal) error) error {
+	return WalkHTMLWithOptions(rd, Options{}, fn)
+}
+
+// WalkHTMLWithOptions is like WalkHTML, but also reads the values
+// of the attributes listed in opts.ClassAttrs.
+func WalkHTMLWithOptions(rd io.Reader, opts Options, fn func(lit Literal) error) error {
+	return walkHTML(newPeekIterator(bufio.NewReader(rd)), opts, fn)
+}
+
+func walkHTML(pit *peekIterator, opts Options, fn func(lit Literal) error) error {
 	var sb strings.Builder
-	var acc []string
 
 	for {
-		for state == stateClassAttr {
-			c, _, err := pit.next()
+		c, peekC, err := pit.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if c != '<' {
+			continue
+		}
+
+		if peekC == '!' {
+			if err := skipDeclaration(pit); err != nil {
+				return err
+			}
+			continue
+		}
+		if !isASCIILetter(peekC) {
+			continue
+		}
+
+		start := pit.peekPos
+		tag, err := readTag(pit, &sb)
+		if err != nil {
+			return err
+		}
+		name, attrs := parseTag(tag)
+		if err := walkClassAttrs(tag, start, attrs, opts, fn); err != nil {
+			return err
+		}
+
+		switch name {
+		case "style":
+			if _, _, err := readRawText(pit, &sb, name); err != nil {
+				return err
+			}
+		case "script":
+			body, bodyPos, err := readRawText(pit, &sb, name)
 			if err != nil {
-				if errors.Is(err, io.EOF) {
-					return nil, errors.New("unterminated class attr")
-				}
-				return nil, err
+				return err
 			}
-			if c == '"' {
-				acc = append(acc, sb.String())
-				sb.Reset()
-				state = stateOther
-			} else {
-				sb.WriteRune(c)
+			if err := walkScript(body, bodyPos, scriptType(attrs), fn); err != nil {
+				return err
 			}
 		}
+	}
+}
 
-		err := pit.skipUntil(classAttrStart...)
+// skipDeclaration skips a comment or a declaration such as the doctype,
+// the iterator is positioned right after the opening '<'.
+func skipDeclaration(pit *peekIterator) error {
+	pit.next()
+	comment := false
+	if pit.peekC == '-' {
+		pit.next()
+		comment = pit.peekC == '-'
+		if comment {
+			pit.next()
+		}
+	}
+
+	dashes := 0
+	if comment {
+		// "<!-->" and "<!--->" are complete, if abrupt, comments.
+		if pit.peekC == '-' {
+			pit.next()
+			dashes = 1
+		}
+		if pit.peekC == '>' {
+			pit.next()
+			return nil
+		}
+	}
+
+	for {
+		c, peekC, err := pit.next()
 		if err != nil {
-			return nil, err
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
 		}
-		if pit.peekC == 0 {
-			return acc, nil
-		} else if pit.peekC != '"' {
-			state = stateClassAttr
+		switch {
+		case c == '>' && (!comment || dashes >= 2):
+			return nil
+		case c == '!' && dashes >= 2 && peekC == '>':
+			// Like browsers, also accept "--!>" as the end of a comment.
+			pit.next()
+			return nil
+		case c == '-':
+			dashes++
+		default:
+			dashes = 0
 		}
 	}
 }
+
+// readTag reads the text of a start tag up to the closing '>', which is
+// consumed but not included. A '>' within a quoted value doesn't end the
+// tag. Reaching the end of the input is not an error.
+func readTag(pit *peekIterator, sb *strings.Builder) (string, error) {
+	sb.Reset()
+	var quoteC rune
+	// Quotes only start a value when they follow an '='.
+	afterEquals := false
+	for {
+		c, _, err := pit.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return sb.String(), nil
+			}
+			return "", err
+		}
+		switch {
+		case quoteC != 0:
+			if c == quoteC {
+				quoteC = 0
+			}
+		case (c == '"' || c == '\'') && afterEquals:
+			quoteC = c
+		case c == '>':
+			return sb.String(), nil
+		}
+		if c == '=' {
+			afterEquals = true
+		} else if c >= utf8.RuneSelf || !isHTMLSpace(byte(c)) {
+			afterEquals = false
+		}
+		sb.WriteRune(c)
+	}
+}
+
+// readRawText reads the contents of an element whose contents are not
+// markup, up to its end tag, which is consumed. It returns the contents
+// along with their position.
+func readRawText(pit *peekIterator, sb *strings.Builder, name string) (string, Position, error) {
+	sb.Reset()
+	start := pit.peekPos
+	endTag := "</" + name
+	for {
+		c, _, err := pit.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return sb.String(), start, nil
+			}
+			return "", Position{}, err
+		}
+		sb.WriteRune(c)
+
+		text := sb.String()
+		if len(text) >= len(endTag) && strings.EqualFold(text[len(text)-len(endTag):], endTag) {
+			if err := pit.skipUntil('>'); err != nil {
+				return "", Position{}, err
+			}
+			return text[:len(text)-len(endTag)], start, nil
+		}
+	}
+}
+
+// walkClassAttrs calls fn for the values of the class-bearing attributes
+// among attrs of the start tag, which begins at start.
+func walkClassAttrs(tag string, start Position, attrs []attribute, opts Options, fn func(lit Literal) error) error {
+	tagLit := Literal{Value: tag, Pos: start}
+	for _, attr := range attrs {
+		syntax, ok := opts.classAttrSyntax(attr.name)
+		if !ok {
+			continue
+		}
+		if attr.unterminated {
+			return fmt.Errorf("unterminated %s attr", attr.name)
+		}
+		if attr.value == "" {
+			continue
+		}
+
+		lit := Literal{Value: attr.value, Pos: tagLit.PositionAt(attr.offset)}
+		var err error
+		switch syntax {
+		case SyntaxClassList:
+			err = fn(lit)
+		case SyntaxHTMX:
+			lit.Value = htmxClassList(lit.Value)
+			err = fn(lit)
+		case SyntaxJS:
+			pit := newPeekIterator(bufio.NewReader(strings.NewReader(lit.Value)))
+			err = walkJS(pit, func(jsLit Literal) error {
+				jsLit.Pos = jsLit.Pos.translate(lit.Pos)
+				return fn(jsLit)
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkScript scans the body of a script element of the given type,
+// which starts at pos. Only JavaScript is scanned, scripts holding data
+// or templates are skipped.
+func walkScript(body string, pos Position, typ string, fn func(lit Literal) error) error {
+	if !slices.Contains(jsTypes, typ) {
+		return nil
+	}
+
+	pit := newPeekIterator(bufio.NewReader(strings.NewReader(body)))
+	return walkJS(pit, func(lit Literal) error {
+		lit.Pos = lit.Pos.translate(pos)
+		return fn(lit)
+	})
+}
+
+type attribute struct {
+	name  string
+	value string
+	// offset is the byte offset of the value within the tag.
+	offset int
+	// unterminated is set for a quoted value that lacks the closing quote.
+	unterminated bool
+}
+
+// parseTag splits the text of a start tag into its lowercase name and
+// attributes, following the HTML tokenization rules. Attribute names are
+// lowercased, values may be double-quoted, single-quoted or unquoted,
+// and there may be whitespace around the '='.
+func parseTag(tag string) (string, []attribute) {
+	i := 0
+	for i < len(tag) && !isHTMLSpace(tag[i]) && tag[i] != '/' {
+		i++
+	}
+	name := strings.ToLower(tag[:i])
+
+	var attrs []attribute
+	for {
+		for i < len(tag) && (isHTMLSpace(tag[i]) || tag[i] == '/') {
+			i++
+		}
+		if i == len(tag) {
+			return name, attrs
+		}
+
+		// The first character belongs to the name even if it's an '='.
+		start := i
+		i++
+		for i < len(tag) && !isHTMLSpace(tag[i]) && tag[i] != '/' && tag[i] != '=' {
+			i++
+		}
+		attr := attribute{name: strings.ToLower(tag[start:i])}
+
+		j := i
+		for j < len(tag) && isHTMLSpace(tag[j]) {
+			j++
+		}
+		if j < len(tag) && tag[j] == '=' {
+			i = j + 1
+			for i < len(tag) && isHTMLSpace(tag[i]) {
+				i++
+			}
+			if i < len(tag) && (tag[i] == '"' || tag[i] == '\'') {
+				quote := tag[i]
+				i++
+				attr.offset = i
+				for i < len(tag) && tag[i] != quote {
+					i++
+				}
+				attr.value = tag[attr.offset:i]
+				attr.unterminated = i == len(tag)
+				i = min(i+1, len(tag))
+			} else {
+				attr.offset = i
+				for i < len(tag) && !isHTMLSpace(tag[i]) {
+					i++
+				}
+				attr.value = tag[attr.offset:i]
+			}
+		}
+		// Like in browsers, only the first of duplicate attributes counts.
+		if !slices.ContainsFunc(attrs, func(a attribute) bool { return a.name == attr.name }) {
+			attrs = append(attrs, attr)
+		}
+	}
+}
+
+func scriptType(attrs []attribute) string {
+	for _, attr := range attrs {
+		if attr.name == "type" {
+			typ, _, _ := strings.Cut(attr.value, ";")
+			return strings.ToLower(strings.TrimSpace(typ))
+		}
+	}
+	return ""
+}
+
+// jsTypes are the script types that denote JavaScript.
+var jsTypes = []string{
+	"", "module",
+	"application/ecmascript", "application/javascript", "application/x-ecmascript",
+	"application/x-javascript", "text/ecmascript", "text/javascript", "text/jscript",
+	"text/livescript", "text/x-ecmascript", "text/x-javascript",
+}
+
+func isASCIILetter(c rune) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isHTMLSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\f', '\r':
+		return true
+	}
+	return false
+}
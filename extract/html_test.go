@@ -0,0 +1,151 @@
+package extract
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+type extractTest struct {
+	name  string
+	input string
+	want  []string
+}
+
+func runExtractTests(t *testing.T, tests []extractTest, extractFn func(input string) ([]string, error)) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractFn(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func fromHTMLString(input string) ([]string, error) {
+	return FromHTML(strings.NewReader(input))
+}
+
+func TestFromHTMLComments(t *testing.T) {
+	runExtractTests(t, []extractTest{
+		{"comment", `<!-- <p class="w-1"> --><p class="w-2">`, []string{"w-2"}},
+		{"abrupt comment", `<!--><div class="w-4">`, []string{"w-4"}},
+		{"abrupt comment with dash", `<!---><div class="w-4">`, []string{"w-4"}},
+		{"empty comment", `<!----><div class="w-4">`, []string{"w-4"}},
+		{"dashes before end", `<!-- a ---><div class="w-4">`, []string{"w-4"}},
+		{"dashes inside", `<!-- a -- b - > class="w-1" --><i class="w-2">`, []string{"w-2"}},
+		{"bang end", `<!-- <p class="w-1"> --!><i class="w-2">`, []string{"w-2"}},
+		{"multiline", "<!--\n<p class=\"w-1\">\n-->\n<p class=\"w-2\">", []string{"w-2"}},
+		{"doctype", `<!DOCTYPE html><html class="w-4">`, []string{"w-4"}},
+		{"unterminated", `<p class="w-2"><!-- <p class="w-1">`, []string{"w-2"}},
+	}, fromHTMLString)
+}
+
+func TestFromHTMLRawText(t *testing.T) {
+	runExtractTests(t, []extractTest{
+		{"style", `<style>.a > .b { x: 1 } /* class="w-1" */</style><p class="w-2">`, []string{"w-2"}},
+		{"style with attributes", `<style media="print">p[class="w-1"] {}</style><p class="w-2">`, []string{"w-2"}},
+		{"uppercase end tag", `<style>a { b: "<p class='w-1'>" }</STYLE ><p class="w-2">`, []string{"w-2"}},
+		{"class on style", `<style class="w-1">p {}</style>`, []string{"w-1"}},
+		{"markup in script", `<script>s = '<p class="w-1">'</script>`, []string{`<p class="w-1">`}},
+		{"script end tag", `<script>a = "m-1"</script><p class="w-2">`, []string{"m-1", "w-2"}},
+	}, fromHTMLString)
+}
+
+func TestFromHTMLScriptTypes(t *testing.T) {
+	const body = `>el.className = "m-1"</script>`
+	runExtractTests(t, []extractTest{
+		{"no type", `<script` + body, []string{"m-1"}},
+		{"empty type", `<script type=""` + body, []string{"m-1"}},
+		{"module", `<script type="module"` + body, []string{"m-1"}},
+		{"javascript", `<script type="text/javascript"` + body, []string{"m-1"}},
+		{"javascript with parameters", `<script type="Text/JavaScript; charset=utf-8"` + body, []string{"m-1"}},
+		{"legacy javascript", `<script type=application/x-javascript` + body, []string{"m-1"}},
+		{"json", `<script type="application/json"` + body, nil},
+		{"ld+json", `<script type="application/ld+json"` + body, nil},
+		{"importmap", `<script type="importmap"` + body, nil},
+		{"unknown", `<script type="text/plain"` + body, nil},
+		{"template", `<script type="text/x-template"><p class="w-2">` + body, nil},
+		{"html template", `<script type="text/html"><p class="w-2"></script><p class="w-4">`, []string{"w-4"}},
+		{"handlebars", `<script type="text/x-handlebars-template"><p class="w-2"></script>`, nil},
+	}, fromHTMLString)
+}
+
+func TestLiteralsFromHTMLScriptPositions(t *testing.T) {
+	lits, err := LiteralsFromHTML(strings.NewReader("<div>\n  <script>\n    x = \"w-4\"</script><p class=\"m-2\">"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Literal{
+		{Value: "w-4", Pos: Position{Offset: 26, Line: 3, Column: 10}, Kind: KindString},
+		{Value: "m-2", Pos: Position{Offset: 49, Line: 3, Column: 33}},
+	}
+	if !slices.Equal(lits, want) {
+		t.Errorf("got %+v, want %+v", lits, want)
+	}
+}
+
+func TestFromHTMLQuoting(t *testing.T) {
+	runExtractTests(t, []extractTest{
+		{"double quotes", `<p class="w-4 m-2">`, []string{"w-4 m-2"}},
+		{"single quotes", `<p class='w-4 m-2'>`, []string{"w-4 m-2"}},
+		{"unquoted", `<p class=w-4>`, []string{"w-4"}},
+		{"unquoted self-closing", `<br class=w-4 />`, []string{"w-4"}},
+		{"unquoted before slash", `<br class=w-4/>`, []string{"w-4/"}},
+		{"spaces around equals", "<p class = \"w-4\"\n>", []string{"w-4"}},
+		{"newline before value", "<p class=\n'w-4'>", []string{"w-4"}},
+		{"uppercase", `<P CLASS="w-4">`, []string{"w-4"}},
+		{"other quote inside", `<p class="w-4 '" id='a"b'>`, []string{`w-4 '`}},
+		{"gt in quoted value", `<p title="a > b" class="w-4">`, []string{"w-4"}},
+		{"gt in single-quoted value", `<p title='>' class='w-4'>`, []string{"w-4"}},
+		{"equals in quoted value", `<a href="?a=b" class="w-4">`, []string{"w-4"}},
+		{"quote in unquoted value", `<p title=a"b class="w-4">`, []string{"w-4"}},
+		{"quote in name", `<p x"y class="w-4">`, []string{"w-4"}},
+		{"duplicate", `<p class="w-4" class="w-8">`, []string{"w-4"}},
+		{"empty", `<p class="" id=a>`, nil},
+		{"no value", `<p class id="w-4">`, nil},
+		{"prefixed name", `<p data-class="w-4" xclass="w-8">`, nil},
+	}, fromHTMLString)
+}
+
+func TestFromHTMLUnterminatedAttr(t *testing.T) {
+	for _, input := range []string{`<p class="w-4>`, `<p class='w-4>`} {
+		if _, err := fromHTMLString(input); err == nil {
+			t.Errorf("FromHTML(%q) returned no error", input)
+		}
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag   string
+		name  string
+		attrs []attribute
+	}{
+		{"p", "p", nil},
+		{"BR/", "br", nil},
+		{`p class="a b"`, "p", []attribute{{name: "class", value: "a b", offset: 9}}},
+		{`p CLASS='a'`, "p", []attribute{{name: "class", value: "a", offset: 9}}},
+		{"p class=a id=b", "p", []attribute{
+			{name: "class", value: "a", offset: 8},
+			{name: "id", value: "b", offset: 13},
+		}},
+		{"p class = \"a\"", "p", []attribute{{name: "class", value: "a", offset: 11}}},
+		{"p hidden class=a", "p", []attribute{{name: "hidden"}, {name: "class", value: "a", offset: 15}}},
+		{"p/class=a", "p", []attribute{{name: "class", value: "a", offset: 8}}},
+		{"p =a", "p", []attribute{{name: "=a"}}},
+		{`p class="a`, "p", []attribute{{name: "class", value: "a", offset: 9, unterminated: true}}},
+		{`p class=`, "p", []attribute{{name: "class", offset: 8}}},
+	}
+	for _, tt := range tests {
+		name, attrs := parseTag(tt.tag)
+		if name != tt.name || !slices.Equal(attrs, tt.attrs) {
+			t.Errorf("parseTag(%q) = %q, %+v, want %q, %+v", tt.tag, name, attrs, tt.name, tt.attrs)
+		}
+	}
+}
@@ -8,12 +8,22 @@ import (
 )
 
 type peekIterator struct {
-	reader *bufio.Reader
-	peekC  rune
+	reader   *bufio.Reader
+	peekC    rune
+	peekSize int
+	peekPos  Position
+	pos      Position
 }
 
 func newPeekIterator(rd *bufio.Reader) *peekIterator {
-	pit := &peekIterator{reader: rd, peekC: unicode.ReplacementChar}
+	// The placeholder is consumed by the first call to next,
+	// which moves peekPos to the first byte.
+	pit := &peekIterator{
+		reader:   rd,
+		peekC:    unicode.ReplacementChar,
+		peekSize: 1,
+		peekPos:  Position{Offset: -1, Line: 1, Column: 0},
+	}
 	pit.next()
 	return pit
 }
@@ -24,31 +34,52 @@ func (pit *peekIterator) next() (rune, rune, error) {
 	}
 	currentC := pit.peekC
 
-	c, _, err := pit.reader.ReadRune()
+	c, size, err := pit.reader.ReadRune()
 	if errors.Is(err, io.EOF) {
 		c = 0
 	} else if err != nil {
 		return 0, 0, err
 	}
+
+	pit.pos = pit.peekPos
+	pit.peekPos.Offset += pit.peekSize
+	if currentC == '\n' {
+		pit.peekPos.Line++
+		pit.peekPos.Column = 1
+	} else {
+		pit.peekPos.Column += pit.peekSize
+	}
 	pit.peekC = c
+	pit.peekSize = size
 
 	return currentC, pit.peekC, nil
 }
 
 func (pit *peekIterator) skipLine() error {
+	skipped := pit.peekSize
 	for {
-		_, isPrefix, err := pit.reader.ReadLine()
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			return err
-		}
-		if !isPrefix {
+		line, err := pit.reader.ReadSlice('\n')
+		skipped += len(line)
+		if err == nil {
 			break
 		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			pit.peekC = 0
+			pit.peekPos.Offset += skipped
+			pit.peekPos.Column += skipped
+			return nil
+		}
+		return err
+	}
+	pit.peekPos = Position{
+		Offset: pit.peekPos.Offset + skipped,
+		Line:   pit.peekPos.Line + 1,
+		Column: 1,
 	}
-	c, _, err := pit.reader.ReadRune()
+	c, size, err := pit.reader.ReadRune()
 	if err != nil {
 		if errors.Is(err, io.EOF) {
 			pit.peekC = 0
@@ -57,6 +88,7 @@ func (pit *peekIterator) skipLine() error {
 		}
 	} else {
 		pit.peekC = c
+		pit.peekSize = size
 	}
 	return nil
 }
@@ -82,6 +114,9 @@ func (pit *peekIterator) skipUntil(delim ...rune) error {
 			if delimPtr == delimLen {
 				return nil
 			}
+		} else if c == delim[0] {
+			// E.g. the second '*' in "**/" may start the delimiter.
+			delimPtr = 1
 		} else {
 			delimPtr = 0
 		}
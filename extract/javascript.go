@@ -4,80 +4,249 @@ import (
 	"bufio"
 	"errors"
 	"io"
+	"slices"
 	"strings"
+	"unicode"
 )
 
-var ErrUnterminatedString = errors.New("unterminated string")
+var (
+	ErrUnterminatedString   = errors.New("unterminated string")
+	ErrUnterminatedTemplate = errors.New("unterminated template literal")
+)
 
 func FromJS(rd io.Reader) ([]string, error) {
-	pit := newPeekIterator(bufio.NewReader(rd))
+	lits, err := LiteralsFromJS(rd)
+	if err != nil {
+		return nil, err
+	}
+	return literalValues(lits), nil
+}
+
+func LiteralsFromJS(rd io.Reader) ([]Literal, error) {
+	return collectLiterals(rd, WalkJS)
+}
 
-	const (
-		stateCode = iota
-		stateString
-	)
+// WalkJS calls fn for every string literal in rd as soon as it is read.
+// The static chunks of template literals are passed as separate literals,
+// and their substitutions are scanned like the rest of the code. Regular
+// expression literals are skipped. Walking stops at the first error
+// returned by fn.
+func WalkJS(rd io.Reader, fn func(lit Literal) error) error {
+	return walkJS(newPeekIterator(bufio.NewReader(rd)), fn)
+}
 
-	state := stateCode
-	var quoteC rune
-	var sb strings.Builder
-	var acc []string
+func walkJS(pit *peekIterator, fn func(lit Literal) error) error {
+	s := &jsScanner{pit: pit, fn: fn}
+	return s.scanCode(false)
+}
+
+type jsScanner struct {
+	pit *peekIterator
+	fn  func(lit Literal) error
+}
+
+// regexKeywords are the keywords after which a '/' starts
+// a regular expression rather than being a division.
+var regexKeywords = []string{
+	"await", "case", "delete", "do", "else", "in", "instanceof",
+	"new", "of", "return", "throw", "typeof", "void", "yield",
+}
+
+// scanCode scans code up to the end of the input or, within a template
+// substitution, up to the '}' that closes it.
+func (s *jsScanner) scanCode(inSubst bool) error {
+	depth := 0
+	// regexAllowed tells whether a '/' would start a regular expression,
+	// which is the case when the previous token can't end an operand.
+	regexAllowed := true
 
 	for {
-		c, peekC, err := pit.next()
+		c, peekC, err := s.pit.next()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
-				if state == stateString {
-					return nil, ErrUnterminatedString
+				if inSubst {
+					return ErrUnterminatedTemplate
 				}
-				return acc, nil
-			} else {
-				return nil, err
+				return nil
 			}
+			return err
 		}
 
-		switch state {
-		case stateCode:
-			if c == '"' || c == '\'' {
-				state = stateString
-				quoteC = c
-			} else if c == '/' {
-				if peekC == '/' {
-					if err := pit.skipLine(); err != nil {
-						return nil, err
-					}
-				} else if peekC == '*' {
-					if err := pit.skipUntil('*', '/'); err != nil {
-						return nil, err
-					}
-				}
+		switch {
+		case c == '"' || c == '\'':
+			if err := s.scanString(c); err != nil {
+				return err
 			}
-		case stateString:
-			if c == '\\' {
-				c2, _, err := pit.next()
-				if err != nil {
-					if errors.Is(err, io.EOF) {
-						return nil, ErrUnterminatedString
-					}
-					return nil, err
+			regexAllowed = false
+		case c == '`':
+			if err := s.scanTemplate(); err != nil {
+				return err
+			}
+			regexAllowed = false
+		case c == '/' && peekC == '/':
+			if err := s.pit.skipLine(); err != nil {
+				return err
+			}
+		case c == '/' && peekC == '*':
+			s.pit.next()
+			if err := s.pit.skipUntil('*', '/'); err != nil {
+				return err
+			}
+		case c == '/':
+			if regexAllowed {
+				if err := s.skipRegex(); err != nil {
+					return err
 				}
-				_, err = sb.WriteRune('\\')
+			}
+			regexAllowed = !regexAllowed
+		case c == '{':
+			depth++
+			regexAllowed = true
+		case c == '}':
+			if inSubst && depth == 0 {
+				return nil
+			}
+			depth--
+			regexAllowed = true
+		case c == ')' || c == ']':
+			regexAllowed = false
+		case (c == '+' || c == '-') && peekC == c:
+			// A postfix "++" or "--" follows an operand and a prefix one
+			// precedes it, either way what may follow doesn't change.
+			s.pit.next()
+		case isJSIdentChar(c):
+			var word strings.Builder
+			word.WriteRune(c)
+			for isJSIdentChar(s.pit.peekC) {
+				c, _, err := s.pit.next()
 				if err != nil {
-					return nil, err
+					return err
 				}
-				_, err = sb.WriteRune(c2)
-				if err != nil {
-					return nil, err
+				word.WriteRune(c)
+			}
+			regexAllowed = slices.Contains(regexKeywords, word.String())
+		case unicode.IsSpace(c):
+		default:
+			regexAllowed = true
+		}
+	}
+}
+
+func (s *jsScanner) scanString(quoteC rune) error {
+	var sb strings.Builder
+	start := s.pit.peekPos
+	for {
+		c, _, err := s.pit.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return ErrUnterminatedString
+			}
+			return err
+		}
+		switch c {
+		case '\\':
+			c2, _, err := s.pit.next()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return ErrUnterminatedString
 				}
-			} else if c == quoteC {
-				acc = append(acc, sb.String())
-				sb.Reset()
-				state = stateCode
-			} else {
-				_, err := sb.WriteRune(c)
-				if err != nil {
-					return nil, err
+				return err
+			}
+			sb.WriteRune(c)
+			sb.WriteRune(c2)
+		case quoteC:
+			return s.fn(Literal{Value: sb.String(), Pos: start, Kind: KindString})
+		default:
+			sb.WriteRune(c)
+		}
+	}
+}
+
+// scanTemplate passes the non-empty static chunks of a template literal
+// to fn and scans its substitutions.
+func (s *jsScanner) scanTemplate() error {
+	var sb strings.Builder
+	start := s.pit.peekPos
+	emit := func() error {
+		if sb.Len() == 0 {
+			return nil
+		}
+		return s.fn(Literal{Value: sb.String(), Pos: start, Kind: KindString})
+	}
+
+	for {
+		c, peekC, err := s.pit.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return ErrUnterminatedTemplate
+			}
+			return err
+		}
+		switch {
+		case c == '\\':
+			c2, _, err := s.pit.next()
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return ErrUnterminatedTemplate
 				}
+				return err
+			}
+			sb.WriteRune(c)
+			sb.WriteRune(c2)
+		case c == '`':
+			return emit()
+		case c == '$' && peekC == '{':
+			s.pit.next()
+			if err := emit(); err != nil {
+				return err
 			}
+			if err := s.scanCode(true); err != nil {
+				return err
+			}
+			sb.Reset()
+			start = s.pit.peekPos
+		default:
+			sb.WriteRune(c)
 		}
 	}
 }
+
+// skipRegex skips the rest of a regular expression literal along with
+// its flags. A '/' within a character class doesn't end it. A line break
+// does, so a division mistaken for a regular expression skips at most
+// the rest of the line.
+func (s *jsScanner) skipRegex() error {
+	inClass := false
+	for {
+		c, _, err := s.pit.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		switch {
+		case c == '\n':
+			return nil
+		case c == '\\':
+			if s.pit.peekC != '\n' {
+				s.pit.next()
+			}
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+		case c == '[':
+			inClass = true
+		case c == '/':
+			for isJSIdentChar(s.pit.peekC) {
+				s.pit.next()
+			}
+			return nil
+		}
+	}
+}
+
+func isJSIdentChar(c rune) bool {
+	return c == '_' || c == '$' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
@@ -0,0 +1,90 @@
+package extract
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func fromJSString(input string) ([]string, error) {
+	return FromJS(strings.NewReader(input))
+}
+
+func TestFromJSRegex(t *testing.T) {
+	runExtractTests(t, []extractTest{
+		{"quote in regex", `const re = /["']/g; x = "w-4"`, []string{"w-4"}},
+		{"escaped slash", `x = /\/"/.test(s) ? "w-4" : ""`, []string{"w-4", ""}},
+		{"slash in class", `x = /[/"]/; y = "w-4"`, []string{"w-4"}},
+		{"after return", "function f() { return /'/ }\nx = 'w-4'", []string{"w-4"}},
+		{"after typeof", `t = typeof /'/; x = "w-4"`, []string{"w-4"}},
+		{"after paren", `x = /'/.test(a) && "w-4"`, []string{"w-4"}},
+		{"division", `x = a / b / c; y = "w-4"`, []string{"w-4"}},
+		{"division after paren", `x = (a) / 2; y = 'w-4'`, []string{"w-4"}},
+		{"division after bracket", `x = a[0] / 2; y = 'w-4'`, []string{"w-4"}},
+		{"division after number", `x = 10 / 2; y = 'w-4'`, []string{"w-4"}},
+		{"division after postfix increment", `a++ / 2; y = 'w-8'; // comment 'w-no'`, []string{"w-8"}},
+		{"division after postfix decrement", `a-- / 2; y = 'w-8'; // comment 'w-no'`, []string{"w-8"}},
+		{"regex after prefix increment", `x = ++/'/.lastIndex; y = 'w-8'`, []string{"w-8"}},
+		{"regex after plus", `x = a + /'/.source + "w-8"`, []string{"w-8"}},
+		{"flags", `x = /a/gimsuy.test("w-8")`, []string{"w-8"}},
+		{"unterminated regex", "x = 1 + /'\ny = 'w-8'", []string{"w-8"}},
+	}, fromJSString)
+}
+
+func TestFromJSComments(t *testing.T) {
+	runExtractTests(t, []extractTest{
+		{"line comment", "// 'w-1'\nx = 'w-2'", []string{"w-2"}},
+		{"block comment", "/* 'w-1' */ x = 'w-2'", []string{"w-2"}},
+		{"doc comment", "/** 'w-1' **/ x = 'w-2'", []string{"w-2"}},
+		{"slashes in string", `x = "//w-1"; y = 'w-2'`, []string{"//w-1", "w-2"}},
+	}, fromJSString)
+}
+
+func TestFromJSTemplateLiterals(t *testing.T) {
+	runExtractTests(t, []extractTest{
+		{"plain", "x = `w-4 m-2`", []string{"w-4 m-2"}},
+		{"empty", "x = ``", nil},
+		{"substitution", "x = `w-4 ${a} m-2`", []string{"w-4 ", " m-2"}},
+		{"adjacent substitutions", "x = `${a}${b}`", nil},
+		{"strings in substitution", "x = `p-2 ${on ? \"bg-red-500\" : 'bg-blue-500'}`", []string{"p-2 ", "bg-red-500", "bg-blue-500"}},
+		{"nested template", "x = `a ${`b ${'c'} d`} e`", []string{"a ", "b ", "c", " d", " e"}},
+		{"braces in substitution", "x = `a ${{k: 'w-4'}.k} b`", []string{"a ", "w-4", " b"}},
+		{"escaped backtick", "x = `a \\` ${'b'}`", []string{"a \\` ", "b"}},
+		{"escaped substitution", "x = `a \\${'b'}`", []string{"a \\${'b'}"}},
+		{"dollar", "x = `$5 w-4`", []string{"$5 w-4"}},
+		{"division after template", "x = `a` / 2; y = 'w-4'", []string{"a", "w-4"}},
+		{"regex in substitution", "x = `${/`/.source} w-4`", []string{" w-4"}},
+	}, fromJSString)
+}
+
+func TestFromJSErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{`x = "w-4`, ErrUnterminatedString},
+		{"x = `w-4", ErrUnterminatedTemplate},
+		{"x = `${a", ErrUnterminatedTemplate},
+	}
+	for _, tt := range tests {
+		if _, err := fromJSString(tt.input); !errors.Is(err, tt.want) {
+			t.Errorf("FromJS(%q) error = %v, want %v", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestLiteralsFromJSTemplatePositions(t *testing.T) {
+	lits, err := LiteralsFromJS(strings.NewReader("x = `a\n${'b'} c`"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Literal{
+		{Value: "a\n", Pos: Position{Offset: 5, Line: 1, Column: 6}, Kind: KindString},
+		{Value: "b", Pos: Position{Offset: 10, Line: 2, Column: 4}, Kind: KindString},
+		{Value: " c", Pos: Position{Offset: 13, Line: 2, Column: 7}, Kind: KindString},
+	}
+	if !slices.Equal(lits, want) {
+		t.Errorf("got %+v, want %+v", lits, want)
+	}
+}
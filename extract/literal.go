@@ -0,0 +1,112 @@
+package extract
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Position describes a location in the source. Offset is a 0-based byte
+// offset, Line and Column are 1-based, Column is counted in bytes.
+type Position struct {
+	Filename string
+	Offset   int
+	Line     int
+	Column   int
+}
+
+func (pos Position) IsValid() bool {
+	return pos.Line > 0
+}
+
+// String returns the position in the "file:line:column" form, omitting
+// the parts that are not known.
+func (pos Position) String() string {
+	s := pos.Filename
+	if pos.IsValid() {
+		if s != "" {
+			s += ":"
+		}
+		s += fmt.Sprintf("%d:%d", pos.Line, pos.Column)
+	}
+	if s == "" {
+		s = "-"
+	}
+	return s
+}
+
+// translate converts a position within text that starts
+// at base into a position within the text containing it.
+func (pos Position) translate(base Position) Position {
+	if pos.Line == 1 {
+		pos.Column += base.Column - 1
+	}
+	pos.Line += base.Line - 1
+	pos.Offset += base.Offset
+	pos.Filename = base.Filename
+	return pos
+}
+
+// LiteralKind tells where a literal was found.
+type LiteralKind int
+
+const (
+	// KindClassValue is the value of a class-bearing attribute,
+	// which is expected to hold only classes.
+	KindClassValue LiteralKind = iota
+	// KindString is a string literal in code, such as JavaScript
+	// or a template action, which may hold any text.
+	KindString
+)
+
+// Literal is an extracted string along with the position
+// of its first character. Value is the raw source text,
+// so byte offsets within it map directly onto the source.
+type Literal struct {
+	Value string
+	Pos   Position
+	Kind  LiteralKind
+}
+
+// PositionAt returns the source position of the byte
+// at the given offset within the literal's value.
+func (lit Literal) PositionAt(offset int) Position {
+	pos := lit.Pos
+	if !pos.IsValid() {
+		return pos
+	}
+	offset = min(max(offset, 0), len(lit.Value))
+	prefix := lit.Value[:offset]
+
+	pos.Offset += offset
+	if i := strings.LastIndexByte(prefix, '\n'); i >= 0 {
+		pos.Line += strings.Count(prefix, "\n")
+		pos.Column = offset - i
+	} else {
+		pos.Column += offset
+	}
+	return pos
+}
+
+func collectLiterals(rd io.Reader, walk func(io.Reader, func(Literal) error) error) ([]Literal, error) {
+	var acc []Literal
+	err := walk(rd, func(lit Literal) error {
+		acc = append(acc, lit)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+func literalValues(lits []Literal) []string {
+	if lits == nil {
+		return nil
+	}
+	strs := make([]string, len(lits))
+	for i, lit := range lits {
+		strs[i] = lit.Value
+	}
+	return strs
+}
@@ -0,0 +1,193 @@
+package extract
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"strings"
+)
+
+// Delims are the action delimiters of Go templates.
+type Delims struct {
+	Left  string
+	Right string
+}
+
+var DefaultDelims = Delims{Left: "{{", Right: "}}"}
+
+// WalkGoHTML is like WalkHTML, but for Go templates. Template actions are
+// blanked out before the markup is scanned, so that e.g. the class value
+// "w-4 {{if .Wide}}w-50%{{end}}" yields just the classes, while the
+// string literals inside actions are passed to fn as separate literals.
+// Literals are passed in the order of their position in the source.
+func WalkGoHTML(rd io.Reader, opts Options, fn func(lit Literal) error) error {
+	delims := opts.TemplateDelims
+	if delims.Left == "" || delims.Right == "" {
+		delims = DefaultDelims
+	}
+	s := &actionStripper{
+		reader: bufio.NewReader(rd),
+		delims: delims,
+		pos:    Position{Line: 1, Column: 1},
+	}
+
+	// The action literals are found when the stripper is read, which is
+	// ahead of the markup, so they are queued until the markup catches up.
+	flush := func(offset int) error {
+		for len(s.lits) > 0 && s.lits[0].Pos.Offset < offset {
+			lit := s.lits[0]
+			s.lits = s.lits[1:]
+			if err := fn(lit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := WalkHTMLWithOptions(s, opts, func(lit Literal) error {
+		if err := flush(lit.Pos.Offset); err != nil {
+			return err
+		}
+		return fn(lit)
+	})
+	if err != nil {
+		return err
+	}
+	return flush(math.MaxInt)
+}
+
+const (
+	stateText = iota
+	stateAction
+	stateActionString
+	stateActionRawString
+	stateActionChar
+	stateActionComment
+)
+
+// actionStripper replaces the bytes of template actions with spaces,
+// except for newlines, so that the positions in the output match the
+// source, and collects the string literals found within the actions.
+type actionStripper struct {
+	reader *bufio.Reader
+	delims Delims
+	state  int
+	// skip is the number of delimiter bytes left to be blanked.
+	skip    int
+	escaped bool
+	pos     Position
+	str     strings.Builder
+	strPos  Position
+	lits    []Literal
+}
+
+func (s *actionStripper) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		c, err := s.reader.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) && s.state != stateText {
+				err = errors.New("unterminated template action")
+			}
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+
+		s.pos.Offset++
+		if c == '\n' {
+			s.pos.Line++
+			s.pos.Column = 1
+		} else {
+			s.pos.Column++
+		}
+
+		if s.step(c) && c != '\n' {
+			c = ' '
+		}
+		p[n] = c
+		n++
+	}
+	return n, nil
+}
+
+// step advances the state by c and reports whether
+// c is a part of an action.
+func (s *actionStripper) step(c byte) bool {
+	if s.skip > 0 {
+		s.skip--
+		return true
+	}
+
+	switch s.state {
+	case stateText:
+		if s.hasPrefix(c, s.delims.Left) {
+			s.state = stateAction
+			s.skip = len(s.delims.Left) - 1
+			return true
+		}
+		return false
+	case stateAction:
+		switch {
+		case s.hasPrefix(c, s.delims.Right):
+			s.state = stateText
+			s.skip = len(s.delims.Right) - 1
+		case s.hasPrefix(c, "/*"):
+			s.state = stateActionComment
+			s.skip = 1
+		case c == '"':
+			s.state = stateActionString
+			s.str.Reset()
+			s.strPos = s.pos
+		case c == '`':
+			s.state = stateActionRawString
+			s.str.Reset()
+			s.strPos = s.pos
+		case c == '\'':
+			s.state = stateActionChar
+		}
+	case stateActionString, stateActionChar:
+		quoteC := byte('"')
+		if s.state == stateActionChar {
+			quoteC = '\''
+		}
+		switch {
+		case s.escaped:
+			s.escaped = false
+		case c == '\\':
+			s.escaped = true
+		case c == quoteC:
+			if s.state == stateActionString {
+				s.lits = append(s.lits, Literal{Value: s.str.String(), Pos: s.strPos, Kind: KindString})
+			}
+			s.state = stateAction
+			return true
+		}
+		s.str.WriteByte(c)
+	case stateActionRawString:
+		if c == '`' {
+			s.lits = append(s.lits, Literal{Value: s.str.String(), Pos: s.strPos, Kind: KindString})
+			s.state = stateAction
+			return true
+		}
+		s.str.WriteByte(c)
+	case stateActionComment:
+		if s.hasPrefix(c, "*/") {
+			s.state = stateAction
+			s.skip = 1
+		}
+	}
+	return true
+}
+
+// hasPrefix reports whether c followed by the buffered input starts with
+// prefix, without consuming the input.
+func (s *actionStripper) hasPrefix(c byte, prefix string) bool {
+	if c != prefix[0] {
+		return false
+	}
+	next, _ := s.reader.Peek(len(prefix) - 1)
+	return string(next) == prefix[1:]
+}
@@ -0,0 +1,111 @@
+package extract
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func goHTMLLiterals(input string, opts Options) ([]Literal, error) {
+	return collectLiterals(strings.NewReader(input), func(rd io.Reader, fn func(Literal) error) error {
+		return WalkGoHTML(rd, opts, fn)
+	})
+}
+
+// blank returns the n spaces an action of n bytes is replaced with.
+func blank(n int) string {
+	return strings.Repeat(" ", n)
+}
+
+func fromGoHTMLString(input string) ([]string, error) {
+	lits, err := goHTMLLiterals(input, Options{})
+	if err != nil {
+		return nil, err
+	}
+	return literalValues(lits), nil
+}
+
+func TestFromGoHTML(t *testing.T) {
+	runExtractTests(t, []extractTest{
+		{"action in class", `<p class="w-4 {{if .Wide}}w-50%{{end}} m-2">`, []string{"w-4 " + blank(12) + "w-50%" + blank(7) + " m-2"}},
+		{"string in action", `<p class="{{ .C "w-8" }}">`, []string{blank(14), "w-8"}},
+		{"raw string", "{{ $c := `w-8\nm-2` }}<p class=\"w-4\">", []string{"w-8\nm-2", "w-4"}},
+		{"escaped quote", `{{ printf "a\"b" }}`, []string{`a\"b`}},
+		{"char literal", `{{ index . '"' }}<p class="w-4">`, []string{"w-4"}},
+		{"comment", `{{/* "w-1" */}}<p class="w-4">`, []string{"w-4"}},
+		{"comment with trim markers", `{{- /* "w-1" */ -}}<p class="w-4">`, []string{"w-4"}},
+		{"action in tag", `<p {{if .A}}hidden{{end}} class="w-4">`, []string{"w-4"}},
+		{"delimiters in string", `{{ "}}" }}<p class="w-4">`, []string{"}}", "w-4"}},
+		{"markup in string", `{{ "<p class='w-1'>" }}`, []string{"<p class='w-1'>"}},
+	}, fromGoHTMLString)
+}
+
+func TestGoHTMLLiteralOrder(t *testing.T) {
+	input := `{{ $a := "w-1" }}<p class="w-2 {{ "w-3" }}">{{ "w-4" }}<script>x = "w-5"</script>{{ "w-6" }}`
+	got, err := fromGoHTMLString(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"w-1", "w-2 " + blank(11), "w-3", "w-4", "w-5", "w-6"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestGoHTMLPositions(t *testing.T) {
+	lits, err := goHTMLLiterals("{{ `a\nb` }}\n<p class=\"{{ \"c\" }} d\">", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Literal{
+		{Value: "a\nb", Pos: Position{Offset: 4, Line: 1, Column: 5}, Kind: KindString},
+		{Value: blank(9) + " d", Pos: Position{Offset: 22, Line: 3, Column: 11}},
+		{Value: "c", Pos: Position{Offset: 26, Line: 3, Column: 15}, Kind: KindString},
+	}
+	if !slices.Equal(lits, want) {
+		t.Errorf("got %+v, want %+v", lits, want)
+	}
+}
+
+func TestGoHTMLCustomDelims(t *testing.T) {
+	opts := Options{TemplateDelims: Delims{Left: "[[", Right: "]]"}}
+	lits, err := goHTMLLiterals(`<p class="w-4 [[ if .A ]]m-2[[ end ]]" x="{{ "w-1" }}">[[ "w-8" ]]`, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"w-4 " + blank(11) + "m-2" + blank(9), "w-8"}
+	if got := literalValues(lits); !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestGoHTMLUnterminated(t *testing.T) {
+	for _, input := range []string{
+		`<p class="w-4">{{ if .A`,
+		`{{ "w-4 }}`,
+		"{{ `w-4 }}",
+		`{{/* w-4 }}`,
+	} {
+		_, err := fromGoHTMLString(input)
+		if err == nil || err.Error() != "unterminated template action" {
+			t.Errorf("%q: error = %v, want unterminated template action", input, err)
+		}
+	}
+}
+
+func TestGoHTMLCallbackError(t *testing.T) {
+	errStop := errors.New("stop")
+	var got []string
+	err := WalkGoHTML(strings.NewReader(`{{ "a" }}{{ "b" }}<p class="c">`), Options{}, func(lit Literal) error {
+		got = append(got, lit.Value)
+		if lit.Value == "b" {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) || !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("got %q and error %v, want [a b] and %v", got, err, errStop)
+	}
+}
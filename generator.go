@@ -27,12 +27,23 @@ type parsedToken struct {
 }
 
 type cssClass struct {
-	Name   string
-	Tokens []parsedToken
-	Props  []CSSProperty
+	Name     string
+	Selector string
+	Variants variantSet
+	Tokens   []parsedToken
+	Props    []CSSProperty
 }
 
 func GenerateCSS(w io.Writer, rcs []RawCSSClass) error {
+	return GenerateCSSWithConfig(w, rcs, defaultConfig)
+}
+
+// GenerateCSSWithConfig writes the rules for rcs to w. Classes with
+// a breakpoint variant are grouped into media queries, which follow
+// the base rules in the order of cfg.Breakpoints. Within each group,
+// dark mode rules follow the rest, and rules with state variants
+// follow the rules without them.
+func GenerateCSSWithConfig(w io.Writer, rcs []RawCSSClass, cfg *Config) error {
 	bw := bufio.NewWriter(w)
 
 	_, err := fmt.Fprintln(bw, "/* Code generated by CSSKit - DO NOT EDIT. */")
@@ -43,37 +54,67 @@ func GenerateCSS(w io.Writer, rcs []RawCSSClass) error {
 	classMap := make(map[string]struct{})
 	var classes []cssClass
 	for _, rc := range rcs {
-		key, err := getClassKey(rc.Tokens)
-		if err != nil {
-			return err
-		}
+		key := getClassKey(rc)
 		if _, exists := classMap[key]; exists {
 			continue
 		} else {
 			classMap[key] = struct{}{}
 		}
-		class, err := parseCSSClass(rc)
+		class, err := parseCSSClass(cfg, rc)
 		if err != nil {
 			return err
 		}
 		classes = append(classes, class)
 	}
 
-	slices.SortFunc(classes, compareClass)
+	slices.SortFunc(classes, func(a, b cssClass) int {
+		if res := compareVariantSets(a.Variants, b.Variants); res != 0 {
+			return res
+		}
+		if res := compareTokens(a, b); res != 0 {
+			return res
+		}
+		return compareStrings(a.Name, b.Name)
+	})
 
-	for _, class := range classes {
-		_, err := fmt.Fprintf(bw, "\n.%s {\n", class.Name)
-		if err != nil {
+	if cfg.Minify {
+		if err := writeMinified(bw, cfg, classes); err != nil {
 			return err
 		}
-		for _, prop := range class.Props {
-			_, err = fmt.Fprintf(bw, "    %s: %s;\n", prop.Property, prop.Value)
-			if err != nil {
+		return bw.Flush()
+	}
+
+	media := ""
+	indent := ""
+	first := false
+
+	for _, class := range classes {
+		if query := class.Variants.mediaQuery(cfg); query != media {
+			if media != "" {
+				if _, err := fmt.Fprintln(bw, "}"); err != nil {
+					return err
+				}
+			}
+			media = query
+			if _, err := fmt.Fprintf(bw, "\n@media %s {\n", media); err != nil {
 				return err
 			}
+			indent = "    "
+			first = true
 		}
-		_, err = fmt.Fprintln(bw, "}")
-		if err != nil {
+		if !first {
+			if _, err := fmt.Fprintln(bw); err != nil {
+				return err
+			}
+		}
+		if err := writeRule(bw, class, indent); err != nil {
+			return err
+		}
+		first = false
+	}
+
+	if media != "" {
+		if _, err := fmt.Fprintln(bw, "}"); err != nil {
 			return err
 		}
 	}
@@ -81,17 +122,36 @@ func GenerateCSS(w io.Writer, rcs []RawCSSClass) error {
 	return bw.Flush()
 }
 
-func getClassKey(tokens []Token) (string, error) {
-	var sb strings.Builder
-	for _, tok := range tokens {
-		if _, err := sb.WriteString(tok.Value); err != nil {
-			return "", err
+func writeRule(w io.Writer, class cssClass, indent string) error {
+	_, err := fmt.Fprintf(w, "%s%s {\n", indent, class.Selector)
+	if err != nil {
+		return err
+	}
+	for _, prop := range class.Props {
+		_, err = fmt.Fprintf(w, "%s    %s: %s;\n", indent, prop.Property, prop.Value)
+		if err != nil {
+			return err
 		}
 	}
-	return sb.String(), nil
+	_, err = fmt.Fprintf(w, "%s}\n", indent)
+	return err
 }
 
-func compareClass(a, b cssClass) int {
+func getClassKey(rc RawCSSClass) string {
+	var sb strings.Builder
+	for _, v := range rc.Variants {
+		sb.WriteString(v)
+		sb.WriteByte(':')
+	}
+	for _, tok := range rc.Tokens {
+		sb.WriteString(tok.Value)
+	}
+	return sb.String()
+}
+
+// compareTokens orders classes by their tokens, it returns 0 only
+// for classes that differ just in their variants.
+func compareTokens(a, b cssClass) int {
 	tokCountA := len(a.Tokens)
 	tokCountB := len(b.Tokens)
 	minTokCount := min(tokCountA, tokCountB)
@@ -143,6 +203,12 @@ func compareClass(a, b cssClass) int {
 		if tokA.Type == TokenHyphen && tokB.Type == TokenHyphen {
 			continue
 		}
+		if tokA.Type == TokenUnit && tokB.Type == TokenUnit {
+			if res := compareUnits(tokA.TextValue, tokB.TextValue); res != 0 {
+				return res
+			}
+			continue
+		}
 		if tokA.Type == TokenHyphen && tokB.Type == TokenUnit {
 			return 1
 		}
@@ -152,11 +218,11 @@ func compareClass(a, b cssClass) int {
 
 		typeNameA := GetTokenTypeName(tokA.Type)
 		typeNameB := GetTokenTypeName(tokB.Type)
-		err := fmt.Errorf("unexpected comparison between %q and	%q tokens", typeNameA, typeNameB)
+		err := fmt.Errorf("unexpected comparison between %q and %q tokens", typeNameA, typeNameB)
 		panic(err)
 	}
 
-	panic(fmt.Errorf("unexpected comparison between the same class"))
+	return 0
 }
 
 func compareStrings(a, b string) int {
@@ -177,15 +243,20 @@ func compareFloats(a, b float32) int {
 	return 0
 }
 
+// compareUnits orders units by unitOrder, units missing from it, which
+// only arbitrary unit matchers can produce, follow in string order.
 func compareUnits(a, b string) int {
 	posA, okA := unitOrder[a]
 	posB, okB := unitOrder[b]
 
-	if !okA {
-		panic(fmt.Errorf("unrecognized unit: %s", a))
-	}
-	if !okB {
-		panic(fmt.Errorf("unrecognized unit: %s", b))
+	if !okA || !okB {
+		if okA != okB {
+			if okA {
+				return -1
+			}
+			return 1
+		}
+		return compareStrings(a, b)
 	}
 
 	if posA < posB {
@@ -196,11 +267,21 @@ func compareUnits(a, b string) int {
 	return 0
 }
 
-func parseCSSClass(rc RawCSSClass) (cssClass, error) {
+func parseCSSClass(cfg *Config, rc RawCSSClass) (cssClass, error) {
 	var sb strings.Builder
 	toks := make([]parsedToken, len(rc.Tokens))
 	props := slices.Clone(rc.Props)
 
+	variants, err := cfg.classifyVariants(rc.Variants)
+	if err != nil {
+		return cssClass{}, err
+	}
+	for _, v := range rc.Variants {
+		if _, err := sb.WriteString(v + ":"); err != nil {
+			return cssClass{}, err
+		}
+	}
+
 	for i, rtok := range rc.Tokens {
 		if _, err := sb.WriteString(rtok.Value); err != nil {
 			return cssClass{}, err
@@ -225,7 +306,13 @@ func parseCSSClass(rc RawCSSClass) (cssClass, error) {
 	}
 
 	identifier := escapeIdentifier(sb.String())
-	return cssClass{Name: identifier, Tokens: toks, Props: props}, nil
+	return cssClass{
+		Name:     identifier,
+		Selector: variants.selector(cfg, identifier),
+		Variants: variants,
+		Tokens:   toks,
+		Props:    props,
+	}, nil
 }
 
 func escapeIdentifier(input string) string {
@@ -237,6 +324,8 @@ func escapeIdentifier(input string) string {
 			sb.WriteString(`\%`)
 		case '.':
 			sb.WriteString(`\.`)
+		case ':':
+			sb.WriteString(`\:`)
 		default:
 			sb.WriteRune(c)
 		}
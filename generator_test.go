@@ -0,0 +1,239 @@
+package csskit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const generatedHeader = "/* Code generated by CSSKit - DO NOT EDIT. */\n"
+
+// generate parses the classes in input and returns the generated CSS
+// without its header.
+func generate(t *testing.T, cfg *Config, input string) string {
+	t.Helper()
+	rcs, err := NewParserWithConfig(NewLexer(input), cfg).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := GenerateCSSWithConfig(&buf, rcs, cfg); err != nil {
+		t.Fatal(err)
+	}
+	css, ok := strings.CutPrefix(buf.String(), generatedHeader)
+	if !ok {
+		t.Fatalf("missing header in:\n%s", buf.String())
+	}
+	return css
+}
+
+func checkGenerated(t *testing.T, cfg *Config, input, want string) {
+	t.Helper()
+	if got := generate(t, cfg, input); got != want {
+		t.Errorf("generated CSS for %q:\n%s\nwant:\n%s", input, got, want)
+	}
+}
+
+func TestGenerateStackedStateVariants(t *testing.T) {
+	const want = `
+.w-4px {
+    width: 4px;
+}
+
+.focus\:hover\:w-4px:focus:hover {
+    width: 4px;
+}
+
+.hover\:focus\:w-4px:hover:focus {
+    width: 4px;
+}
+`
+	checkGenerated(t, defaultConfig, "hover:focus:w-4px focus:hover:w-4px w-4px", want)
+	checkGenerated(t, defaultConfig, "focus:hover:w-4px w-4px hover:focus:w-4px", want)
+}
+
+func TestGeneratePermutedDarkBreakpoint(t *testing.T) {
+	const want = `
+@media (min-width: 768px) and (prefers-color-scheme: dark) {
+    .dark\:md\:w-4px {
+        width: 4px;
+    }
+
+    .md\:dark\:w-4px {
+        width: 4px;
+    }
+}
+`
+	checkGenerated(t, defaultConfig, "md:dark:w-4px dark:md:w-4px", want)
+	checkGenerated(t, defaultConfig, "dark:md:w-4px md:dark:w-4px", want)
+}
+
+func TestGenerateBreakpoints(t *testing.T) {
+	const want = `
+.m-2 {
+    margin: 0.5000rem;
+}
+
+.w-4 {
+    width: 1.0000rem;
+}
+
+@media (min-width: 640px) {
+    .sm\:m-2 {
+        margin: 0.5000rem;
+    }
+}
+
+@media (min-width: 768px) {
+    .md\:m-2 {
+        margin: 0.5000rem;
+    }
+
+    .md\:w-4 {
+        width: 1.0000rem;
+    }
+
+    .md\:hover\:w-4:hover {
+        width: 1.0000rem;
+    }
+}
+
+@media (min-width: 1024px) {
+    .lg\:w-4 {
+        width: 1.0000rem;
+    }
+}
+
+@media (min-width: 1280px) {
+    .xl\:p-1 {
+        padding: 0.2500rem;
+    }
+}
+`
+	checkGenerated(t, defaultConfig, "lg:w-4 md:w-4 w-4 sm:m-2 md:hover:w-4 md:m-2 xl:p-1 m-2", want)
+	checkGenerated(t, defaultConfig, "xl:p-1 md:hover:w-4 m-2 md:m-2 sm:m-2 w-4 md:w-4 lg:w-4", want)
+}
+
+func TestGenerateCustomBreakpoints(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Breakpoints = []Breakpoint{{Name: "tablet", MinWidth: "40em"}, {Name: "desktop", MinWidth: "64em"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The default breakpoints are replaced, so "md:w-4" is unknown.
+	const want = `
+.w-2 {
+    width: 0.5000rem;
+}
+
+@media (min-width: 40em) {
+    .tablet\:w-4 {
+        width: 1.0000rem;
+    }
+}
+
+@media (min-width: 64em) {
+    .desktop\:w-4 {
+        width: 1.0000rem;
+    }
+}
+`
+	checkGenerated(t, cfg, "desktop:w-4 tablet:w-4 md:w-4 w-2", want)
+}
+
+func TestValidateBreakpoints(t *testing.T) {
+	tests := []struct {
+		name        string
+		breakpoints []Breakpoint
+	}{
+		{"invalid name", []Breakpoint{{Name: "2xl", MinWidth: "1536px"}}},
+		{"duplicate", []Breakpoint{{Name: "sm", MinWidth: "1px"}, {Name: "sm", MinWidth: "2px"}}},
+		{"state variant", []Breakpoint{{Name: "hover", MinWidth: "1px"}}},
+		{"dark variant", []Breakpoint{{Name: "dark", MinWidth: "1px"}}},
+		{"missing minWidth", []Breakpoint{{Name: "sm"}}},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Breakpoints = tt.breakpoints
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: Validate() accepted %+v", tt.name, tt.breakpoints)
+		}
+	}
+}
+
+const darkInput = "dark:bg-red-500 md:dark:w-4 dark:hover:w-4 bg-red-500 md:w-4"
+
+func TestGenerateDarkModeMedia(t *testing.T) {
+	const want = `
+.bg-red-500 {
+    background-color: #ef4444;
+}
+
+@media (prefers-color-scheme: dark) {
+    .dark\:bg-red-500 {
+        background-color: #ef4444;
+    }
+
+    .dark\:hover\:w-4:hover {
+        width: 1.0000rem;
+    }
+}
+
+@media (min-width: 768px) {
+    .md\:w-4 {
+        width: 1.0000rem;
+    }
+}
+
+@media (min-width: 768px) and (prefers-color-scheme: dark) {
+    .md\:dark\:w-4 {
+        width: 1.0000rem;
+    }
+}
+`
+	checkGenerated(t, defaultConfig, darkInput, want)
+}
+
+func TestGenerateDarkModeClass(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DarkMode = DarkModeClass
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `
+.bg-red-500 {
+    background-color: #ef4444;
+}
+
+.dark .dark\:bg-red-500 {
+    background-color: #ef4444;
+}
+
+.dark .dark\:hover\:w-4:hover {
+    width: 1.0000rem;
+}
+
+@media (min-width: 768px) {
+    .md\:w-4 {
+        width: 1.0000rem;
+    }
+
+    .dark .md\:dark\:w-4 {
+        width: 1.0000rem;
+    }
+}
+`
+	checkGenerated(t, cfg, darkInput, want)
+}
+
+func TestValidateDarkMode(t *testing.T) {
+	for _, mode := range []string{"", "Media", "selector"} {
+		cfg := DefaultConfig()
+		cfg.DarkMode = mode
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate() accepted darkMode %q", mode)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package csskit
+
+import "slices"
+
+// patternIndex is a trie over the leading literal keywords of the patterns,
+// e.g. a "max-w-{size}" pattern is stored under "max" -> "w". Patterns that
+// don't start with a literal keyword are stored at the root. Looking up a
+// class only visits the nodes along its own keywords, so it doesn't depend
+// on the number of registered patterns.
+type patternIndex struct {
+	root indexNode
+}
+
+type indexNode struct {
+	children map[string]*indexNode
+	// patterns holds the indices of the patterns whose
+	// literal keyword prefix ends at this node.
+	patterns []int
+}
+
+func (idx *patternIndex) insert(i int, cp *ClassPattern) {
+	nodes := []*indexNode{&idx.root}
+
+	for m := 0; m < len(cp.Matchers); m += 2 {
+		matcher := cp.Matchers[m]
+		if matcher.TokT != TokenKeyword {
+			break
+		}
+		if matcher.ValT != ValueFixed && matcher.ValT != ValueOneOf {
+			break
+		}
+		var next []*indexNode
+		for _, node := range nodes {
+			for _, val := range matcher.Values {
+				next = append(next, node.child(val))
+			}
+		}
+		nodes = next
+		if m+1 < len(cp.Matchers) && cp.Matchers[m+1].TokT != TokenHyphen {
+			break
+		}
+	}
+
+	for _, node := range nodes {
+		node.patterns = append(node.patterns, i)
+	}
+}
+
+func (node *indexNode) child(key string) *indexNode {
+	if node.children == nil {
+		node.children = make(map[string]*indexNode)
+	}
+	child, ok := node.children[key]
+	if !ok {
+		child = &indexNode{}
+		node.children[key] = child
+	}
+	return child
+}
+
+// lookup appends the indices of the patterns that may match tokens
+// to dst, in the order the patterns were registered.
+func (idx *patternIndex) lookup(dst []int, tokens []Token) []int {
+	start := len(dst)
+	node := &idx.root
+	dst = append(dst, node.patterns...)
+
+	for t := 0; t < len(tokens) && tokens[t].Type == TokenKeyword; t += 2 {
+		node = node.children[tokens[t].Value]
+		if node == nil {
+			break
+		}
+		dst = append(dst, node.patterns...)
+	}
+
+	slices.Sort(dst[start:])
+	return dst
+}
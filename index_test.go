@@ -0,0 +1,100 @@
+package csskit
+
+import (
+	"fmt"
+	"testing"
+)
+
+const benchPatternCount = 500
+
+func benchKeyword(i int) string {
+	kw := []byte{'u'}
+	for {
+		kw = append(kw, byte('a'+i%26))
+		i /= 26
+		if i == 0 {
+			return string(kw)
+		}
+	}
+}
+
+func benchRegistry(b *testing.B) *Registry {
+	reg := DefaultRegistry()
+	for i := 0; i < benchPatternCount; i++ {
+		kw := benchKeyword(i)
+		cp, err := PatternDef{
+			Keywords:   []string{kw},
+			Slot:       SlotSize,
+			Properties: []string{fmt.Sprintf("--%s: {size}", kw)},
+		}.Pattern()
+		if err == nil {
+			err = reg.Register(cp)
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	return reg
+}
+
+func benchTokens(b *testing.B) [][]Token {
+	classes := []string{"w-4", "mx-2", "bg-blue-500", "text-gray-50", "unknown-4"}
+	for i := 0; i < benchPatternCount; i += 50 {
+		classes = append(classes, benchKeyword(i)+"-8px")
+	}
+
+	var tokenSets [][]Token
+	for _, class := range classes {
+		lex := NewLexer(class)
+		var tokens []Token
+		for tok := lex.NextToken(); tok.Type != TokenEOF; tok = lex.NextToken() {
+			tokens = append(tokens, tok)
+		}
+		tokenSets = append(tokenSets, tokens)
+	}
+	return tokenSets
+}
+
+// parseClassLinear is the lookup used before the index,
+// trying every registered pattern in order.
+func parseClassLinear(cfg *Config, tokens []Token) (RawCSSClass, error) {
+	patterns := cfg.registry().patterns
+	for i := range patterns {
+		pattern := &patterns[i]
+		if !cfg.groupEnabled(pattern.Group) {
+			continue
+		}
+		if !matchPattern(cfg, pattern, tokens) {
+			continue
+		}
+		props, err := pattern.Generate(cfg, tokens)
+		if err != nil {
+			return RawCSSClass{}, fmt.Errorf("generation error: %v", err)
+		}
+		return RawCSSClass{Tokens: tokens, Props: props}, nil
+	}
+	return RawCSSClass{}, fmt.Errorf("%w for tokens: %v", ErrNoMatchingPattern, tokens)
+}
+
+func BenchmarkParseClass(b *testing.B) {
+	cfg := DefaultConfig()
+	cfg.Registry = benchRegistry(b)
+	tokenSets := benchTokens(b)
+
+	b.Run("indexed", func(b *testing.B) {
+		p := NewParserWithConfig(nil, cfg)
+		for i := 0; i < b.N; i++ {
+			for _, tokens := range tokenSets {
+				p.parseClass(tokens)
+			}
+		}
+	})
+
+	b.Run("linear", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, tokens := range tokenSets {
+				parseClassLinear(cfg, tokens)
+			}
+		}
+	})
+}
@@ -3,6 +3,7 @@ package csskit
 import (
 	"fmt"
 	"unicode"
+	"unicode/utf8"
 )
 
 type TokenType int
@@ -16,20 +17,23 @@ const (
 	TokenSpace
 	TokenGarbage
 	TokenEOF
+	TokenColon
 )
 
 type Token struct {
 	Type  TokenType
 	Value string
+	// Offset is the byte offset of the token within the lexer input.
+	Offset int
 }
 
+// Lexer splits class lists into tokens. The class grammar is ASCII-only,
+// so the lexer works on the bytes of the input and the token values are
+// substrings of it. A Lexer can be reused for another input with Reset.
 type Lexer struct {
-	input    []rune
-	inputLen int
-	pos      int
-	currChar rune
-	peekChar rune
-	prevTok  Token
+	input   string
+	pos     int
+	prevTok Token
 }
 
 func GetTokenTypeName(tt TokenType) string {
@@ -48,106 +52,108 @@ func GetTokenTypeName(tt TokenType) string {
 		return "garbage"
 	case TokenEOF:
 		return "EOF"
+	case TokenColon:
+		return "colon"
 	default:
 		panic(fmt.Errorf("unrecognized token type: %d", tt))
 	}
 }
 
 func NewLexer(input string) *Lexer {
-	inputRunes := []rune(input)
-	l := &Lexer{input: inputRunes, inputLen: len(inputRunes)}
-	if l.inputLen > 0 {
-		l.currChar = l.input[0]
-	}
-	if l.inputLen > 1 {
-		l.peekChar = l.input[1]
-	}
-	return l
+	return &Lexer{input: input}
 }
 
-func (l *Lexer) readChar() {
-	l.currChar = l.peekChar
-	l.pos++
-	if l.pos+1 >= l.inputLen {
-		l.peekChar = 0
-	} else {
-		l.peekChar = l.input[l.pos+1]
-	}
+// Reset makes the lexer start over on a new input.
+func (l *Lexer) Reset(input string) {
+	*l = Lexer{input: input}
 }
 
 func (l *Lexer) NextToken() Token {
 	var tok Token
 	for {
+		start := l.pos
+		if start >= len(l.input) {
+			l.prevTok = Token{Type: TokenEOF, Value: "", Offset: start}
+			return l.prevTok
+		}
+
+		c := l.input[start]
 		switch {
-		case isLowerLetter(l.currChar):
+		case isLowerLetter(rune(c)):
+			l.skipWhile(isLowerLetter)
 			if l.prevTok.Type == TokenNumber {
-				tok.Value = l.readUnit()
 				tok.Type = TokenUnit
 			} else {
-				tok.Value = l.readKeyword()
 				tok.Type = TokenKeyword
 			}
-		case isDigit(l.currChar):
-			tok.Value = l.readNumber()
-			tok.Type = TokenNumber
-		case l.currChar == '%' && l.prevTok.Type == TokenNumber:
-			tok = Token{Type: TokenUnit, Value: "%"}
-			l.readChar()
-		case l.currChar == '-':
-			tok = Token{Type: TokenHyphen, Value: "-"}
-			l.readChar()
-		case unicode.IsSpace(l.currChar):
-			tok = Token{Type: TokenSpace, Value: " "}
-			l.readChar()
-			for unicode.IsSpace(l.currChar) {
-				l.readChar()
+			tok.Value = l.input[start:l.pos]
+		case isDigit(rune(c)):
+			l.readNumber()
+			tok = Token{Type: TokenNumber, Value: l.input[start:l.pos]}
+		case c == '%' && l.prevTok.Type == TokenNumber:
+			l.pos++
+			tok = Token{Type: TokenUnit, Value: l.input[start:l.pos]}
+		case c == '-':
+			l.pos++
+			tok = Token{Type: TokenHyphen, Value: l.input[start:l.pos]}
+		case c == ':':
+			l.pos++
+			tok = Token{Type: TokenColon, Value: l.input[start:l.pos]}
+		case l.isSpace():
+			for l.pos < len(l.input) && l.isSpace() {
+				l.skipChar()
 			}
-		case l.currChar == 0:
-			tok = Token{Type: TokenEOF, Value: ""}
+			tok = Token{Type: TokenSpace, Value: " "}
 		case l.prevTok.Type == TokenGarbage:
-			l.readChar()
+			l.skipChar()
 			continue
 		default:
+			l.skipChar()
 			tok = Token{Type: TokenGarbage, Value: ""}
-			l.readChar()
 		}
 
+		tok.Offset = start
 		l.prevTok = tok
 		return tok
 	}
 }
 
-func (l *Lexer) readKeyword() string {
-	start := l.pos
-	l.readChar()
-	for isLowerLetter(l.currChar) {
-		l.readChar()
+func (l *Lexer) readNumber() {
+	l.skipWhile(isDigit)
+	if l.pos+1 < len(l.input) && l.input[l.pos] == '.' && isDigit(rune(l.input[l.pos+1])) {
+		l.pos++
+		l.skipWhile(isDigit)
 	}
-	return string(l.input[start:l.pos])
 }
 
-func (l *Lexer) readNumber() string {
-	start := l.pos
-	l.readChar()
-	for isDigit(l.currChar) {
-		l.readChar()
+func (l *Lexer) skipWhile(accept func(rune) bool) {
+	for l.pos < len(l.input) && accept(rune(l.input[l.pos])) {
+		l.pos++
 	}
-	if l.currChar == '.' && isDigit(l.peekChar) {
-		l.readChar()
-		for isDigit(l.currChar) {
-			l.readChar()
-		}
+}
+
+// skipChar advances past the character at the current position,
+// which may be a multi-byte one outside of the class grammar.
+func (l *Lexer) skipChar() {
+	if c := l.input[l.pos]; c < utf8.RuneSelf {
+		l.pos++
+		return
 	}
-	return string(l.input[start:l.pos])
+	_, size := utf8.DecodeRuneInString(l.input[l.pos:])
+	l.pos += size
 }
 
-func (l *Lexer) readUnit() string {
-	start := l.pos
-	l.readChar()
-	for isLowerLetter(l.currChar) {
-		l.readChar()
+func (l *Lexer) isSpace() bool {
+	c := l.input[l.pos]
+	if c < utf8.RuneSelf {
+		switch c {
+		case ' ', '\t', '\n', '\v', '\f', '\r':
+			return true
+		}
+		return false
 	}
-	return string(l.input[start:l.pos])
+	r, _ := utf8.DecodeRuneInString(l.input[l.pos:])
+	return unicode.IsSpace(r)
 }
 
 func isLowerLetter(c rune) bool {
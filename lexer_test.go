@@ -0,0 +1,230 @@
+package csskit
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+// runeLexer is the lexer used before the byte-oriented one,
+// converting the input to runes and allocating every token value.
+type runeLexer struct {
+	input    []rune
+	inputLen int
+	pos      int
+	offset   int
+	currChar rune
+	peekChar rune
+	prevTok  Token
+}
+
+func newRuneLexer(input string) *runeLexer {
+	inputRunes := []rune(input)
+	l := &runeLexer{input: inputRunes, inputLen: len(inputRunes)}
+	if l.inputLen > 0 {
+		l.currChar = l.input[0]
+	}
+	if l.inputLen > 1 {
+		l.peekChar = l.input[1]
+	}
+	return l
+}
+
+func (l *runeLexer) readChar() {
+	if l.currChar != 0 {
+		l.offset += utf8.RuneLen(l.currChar)
+	}
+	l.currChar = l.peekChar
+	l.pos++
+	if l.pos+1 >= l.inputLen {
+		l.peekChar = 0
+	} else {
+		l.peekChar = l.input[l.pos+1]
+	}
+}
+
+func (l *runeLexer) NextToken() Token {
+	var tok Token
+	for {
+		start := l.offset
+		switch {
+		case isLowerLetter(l.currChar):
+			if l.prevTok.Type == TokenNumber {
+				tok.Value = l.readLetters()
+				tok.Type = TokenUnit
+			} else {
+				tok.Value = l.readLetters()
+				tok.Type = TokenKeyword
+			}
+		case isDigit(l.currChar):
+			tok.Value = l.readNumber()
+			tok.Type = TokenNumber
+		case l.currChar == '%' && l.prevTok.Type == TokenNumber:
+			tok = Token{Type: TokenUnit, Value: "%"}
+			l.readChar()
+		case l.currChar == '-':
+			tok = Token{Type: TokenHyphen, Value: "-"}
+			l.readChar()
+		case l.currChar == ':':
+			tok = Token{Type: TokenColon, Value: ":"}
+			l.readChar()
+		case unicode.IsSpace(l.currChar):
+			tok = Token{Type: TokenSpace, Value: " "}
+			l.readChar()
+			for unicode.IsSpace(l.currChar) {
+				l.readChar()
+			}
+		case l.currChar == 0:
+			tok = Token{Type: TokenEOF, Value: ""}
+		case l.prevTok.Type == TokenGarbage:
+			l.readChar()
+			continue
+		default:
+			tok = Token{Type: TokenGarbage, Value: ""}
+			l.readChar()
+		}
+
+		tok.Offset = start
+		l.prevTok = tok
+		return tok
+	}
+}
+
+func (l *runeLexer) readLetters() string {
+	start := l.pos
+	l.readChar()
+	for isLowerLetter(l.currChar) {
+		l.readChar()
+	}
+	return string(l.input[start:l.pos])
+}
+
+func (l *runeLexer) readNumber() string {
+	start := l.pos
+	l.readChar()
+	for isDigit(l.currChar) {
+		l.readChar()
+	}
+	if l.currChar == '.' && isDigit(l.peekChar) {
+		l.readChar()
+		for isDigit(l.currChar) {
+			l.readChar()
+		}
+	}
+	return string(l.input[start:l.pos])
+}
+
+func benchInput() string {
+	line := `flex items-center md:w-4 hover:bg-blue-500 mx-2.5 w-50% p-8px ` +
+		`dark:text-gray-50 sm:focus-visible:border-red-300 {{ .Title }} héllo `
+	return strings.Repeat(line, 64)
+}
+
+func BenchmarkLexer(b *testing.B) {
+	input := benchInput()
+
+	b.Run("bytes", func(b *testing.B) {
+		b.SetBytes(int64(len(input)))
+		b.ReportAllocs()
+		lex := NewLexer("")
+		for i := 0; i < b.N; i++ {
+			lex.Reset(input)
+			for lex.NextToken().Type != TokenEOF {
+			}
+		}
+	})
+
+	b.Run("runes", func(b *testing.B) {
+		b.SetBytes(int64(len(input)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			lex := newRuneLexer(input)
+			for lex.NextToken().Type != TokenEOF {
+			}
+		}
+	})
+}
+
+// TestTokenTypeValues guards the values of the exported token types,
+// new types must be added after the existing ones.
+func TestTokenTypeValues(t *testing.T) {
+	types := []TokenType{
+		TokenKeyword, TokenNumber, TokenUnit, TokenHyphen,
+		TokenSpace, TokenGarbage, TokenEOF, TokenColon,
+	}
+	for i, tt := range types {
+		if int(tt) != i+1 {
+			t.Errorf("%s = %d, want %d", GetTokenTypeName(tt), tt, i+1)
+		}
+	}
+}
+
+type lexedToken struct {
+	Type   TokenType
+	Value  string
+	Offset int
+}
+
+func lexAll(next func() Token) []lexedToken {
+	var toks []lexedToken
+	for {
+		tok := next()
+		toks = append(toks, lexedToken{tok.Type, tok.Value, tok.Offset})
+		if tok.Type == TokenEOF {
+			return toks
+		}
+	}
+}
+
+// TestLexerMatchesRuneLexer checks that the byte lexer emits the same
+// tokens as the rune lexer it replaced.
+func TestLexerMatchesRuneLexer(t *testing.T) {
+	inputs := []string{
+		benchInput(),
+		"",
+		"w-4",
+		"héllo w-4 ünïcödé-2 日本-3 w-4日",
+		"w-4\u00a0m-2\u2003p-1\u0085x",
+		"w-4\vm-2\fp-1\r\n\tx",
+		"w-50%% 4px -4 2.5.5 3. .5 md::w-4 a--b",
+	}
+	for _, input := range inputs {
+		want := lexAll(newRuneLexer(input).NextToken)
+		got := lexAll(NewLexer(input).NextToken)
+		if !slices.Equal(got, want) {
+			t.Errorf("%q:\ngot  %v\nwant %v", input, got, want)
+		}
+	}
+}
+
+// TestLexerInvalidUTF8 compares the tokens without their offsets, as
+// the rune lexer counted the invalid bytes as 3-byte replacement runes.
+func TestLexerInvalidUTF8(t *testing.T) {
+	for _, input := range []string{"a\xffb w-4", "w-4\xc3", "\xe2\x82 m-2"} {
+		want := lexAll(newRuneLexer(input).NextToken)
+		got := lexAll(NewLexer(input).NextToken)
+		for i := range want {
+			want[i].Offset = 0
+		}
+		for i := range got {
+			got[i].Offset = 0
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%q:\ngot  %v\nwant %v", input, got, want)
+		}
+	}
+}
+
+// TestLexerNUL checks that a NUL byte is garbage like any other control
+// character, while the rune lexer took it for the end of the input.
+func TestLexerNUL(t *testing.T) {
+	for _, input := range []string{"w-4\x00m-2", "\x00", "\x00\x00w-4"} {
+		want := lexAll(newRuneLexer(strings.ReplaceAll(input, "\x00", "\x01")).NextToken)
+		got := lexAll(NewLexer(input).NextToken)
+		if !slices.Equal(got, want) {
+			t.Errorf("%q:\ngot  %v\nwant %v", input, got, want)
+		}
+	}
+}
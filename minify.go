@@ -0,0 +1,217 @@
+package csskit
+
+import (
+	"bufio"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type minRule struct {
+	selectors []string
+	props     []CSSProperty
+}
+
+// writeMinified writes the sorted classes without whitespace. Rules with
+// identical declarations within the same media query are merged, as long
+// as no rule between them sets any of the same properties, or their
+// shorthands or longhands, so that the cascade is not affected.
+func writeMinified(bw *bufio.Writer, cfg *Config, classes []cssClass) error {
+	for start := 0; start < len(classes); {
+		media := classes[start].Variants.mediaQuery(cfg)
+		end := start + 1
+		for end < len(classes) && classes[end].Variants.mediaQuery(cfg) == media {
+			end++
+		}
+
+		rules := mergeRules(classes[start:end])
+
+		if media != "" {
+			if _, err := fmt.Fprintf(bw, "@media %s{", minifyMediaQuery(media)); err != nil {
+				return err
+			}
+		}
+		for _, rule := range rules {
+			if err := writeMinRule(bw, rule); err != nil {
+				return err
+			}
+		}
+		if media != "" {
+			if _, err := bw.WriteString("}"); err != nil {
+				return err
+			}
+		}
+
+		start = end
+	}
+
+	_, err := fmt.Fprintln(bw)
+	return err
+}
+
+func mergeRules(classes []cssClass) []minRule {
+	var rules []minRule
+
+	for _, class := range classes {
+		props := make([]CSSProperty, len(class.Props))
+		for i, prop := range class.Props {
+			props[i] = CSSProperty{Property: prop.Property, Value: minifyValue(prop.Value)}
+		}
+
+		merged := false
+		for i := len(rules) - 1; i >= 0; i-- {
+			if slices.Equal(rules[i].props, props) {
+				rules[i].selectors = append(rules[i].selectors, class.Selector)
+				merged = true
+				break
+			}
+			if sharesProperty(rules[i].props, props) {
+				break
+			}
+		}
+		if !merged {
+			rules = append(rules, minRule{selectors: []string{class.Selector}, props: props})
+		}
+	}
+
+	return rules
+}
+
+// sharesProperty reports whether the declarations of a and b can
+// override each other, which includes a shorthand and its longhands.
+func sharesProperty(a, b []CSSProperty) bool {
+	for _, pa := range a {
+		for _, pb := range b {
+			if propertiesOverlap(pa.Property, pb.Property) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// propertiesOverlap reports whether a and b are the same property or one
+// is a shorthand setting the other, e.g. "margin" and "margin-left" or
+// "border" and "border-top-color".
+func propertiesOverlap(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return a == b || (strings.HasPrefix(b, a) && b[len(a)] == '-')
+}
+
+func writeMinRule(bw *bufio.Writer, rule minRule) error {
+	if _, err := bw.WriteString(strings.Join(rule.selectors, ",")); err != nil {
+		return err
+	}
+	if err := bw.WriteByte('{'); err != nil {
+		return err
+	}
+	for i, prop := range rule.props {
+		if i > 0 {
+			if err := bw.WriteByte(';'); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(bw, "%s:%s", prop.Property, prop.Value); err != nil {
+			return err
+		}
+	}
+	return bw.WriteByte('}')
+}
+
+func minifyMediaQuery(query string) string {
+	return strings.ReplaceAll(query, ": ", ":")
+}
+
+// minifyValue shortens the numbers and hex colors within a value,
+// e.g. "0.2500rem" becomes ".25rem" and "#aabbcc" becomes "#abc".
+func minifyValue(value string) string {
+	var sb strings.Builder
+	// depth is the nesting level of parentheses, within functions
+	// like calc() zero lengths must keep their units.
+	depth := 0
+	for i := 0; i < len(value); {
+		c := value[i]
+		switch {
+		case c == '#':
+			j := i + 1
+			for j < len(value) && isHexDigit(value[j]) {
+				j++
+			}
+			sb.WriteString(shortenHex(value[i:j]))
+			i = j
+		case isDigit(rune(c)) || (c == '.' && i+1 < len(value) && isDigit(rune(value[i+1]))):
+			j := i
+			for j < len(value) && (isDigit(rune(value[j])) || value[j] == '.') {
+				j++
+			}
+			k := j
+			for k < len(value) && (isLowerLetter(rune(value[k])) || value[k] == '%') {
+				k++
+			}
+			sb.WriteString(shortenNumber(value[i:j], value[j:k], depth > 0))
+			i = k
+		case isLowerLetter(rune(c)) || c == '-':
+			// Skip over identifiers so that digits within
+			// them are not treated as numbers.
+			j := i
+			for j < len(value) && (isLowerLetter(rune(value[j])) || isDigit(rune(value[j])) || value[j] == '-') {
+				j++
+			}
+			sb.WriteString(value[i:j])
+			i = j
+		default:
+			switch c {
+			case '(':
+				depth++
+			case ')':
+				depth = max(depth-1, 0)
+			}
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String()
+}
+
+func shortenNumber(num, unit string, keepUnit bool) string {
+	if strings.Contains(num, ".") {
+		num = strings.TrimRight(num, "0")
+		num = strings.TrimSuffix(num, ".")
+	}
+	num = strings.TrimLeft(num, "0")
+	if num == "" {
+		if keepUnit {
+			return "0" + unit
+		}
+		switch unit {
+		case "rem", "px", "vw", "vh":
+			return "0"
+		}
+		return "0" + unit
+	}
+	return num + unit
+}
+
+func shortenHex(hex string) string {
+	digits := hex[1:]
+	if len(digits) != 6 && len(digits) != 8 {
+		return hex
+	}
+	for i := 0; i < len(digits); i += 2 {
+		if digits[i] != digits[i+1] {
+			return hex
+		}
+	}
+	var sb strings.Builder
+	sb.WriteByte('#')
+	for i := 0; i < len(digits); i += 2 {
+		sb.WriteByte(digits[i])
+	}
+	return sb.String()
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(rune(c)) || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
@@ -0,0 +1,145 @@
+package csskit
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMinifyValue(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"0.2500rem", ".25rem"},
+		{"1.0px", "1px"},
+		{"10px", "10px"},
+		{"100%", "100%"},
+		{"0.5%", ".5%"},
+		{"0rem", "0"},
+		{"0.0px", "0"},
+		{"0%", "0%"},
+		{"0s", "0s"},
+		{"0 0 0.5rem", "0 0 .5rem"},
+		{"#aabbcc", "#abc"},
+		{"#AABBCC", "#ABC"},
+		{"#aabbccdd", "#abcd"},
+		{"#aabbcd", "#aabbcd"},
+		{"#abc", "#abc"},
+		{"rgb(0 0 0 / 0.50)", "rgb(0 0 0 / .5)"},
+		{"calc(0px + 1.50rem)", "calc(0px + 1.5rem)"},
+		{"calc(100% - 0rem) 0rem", "calc(100% - 0rem) 0"},
+		{"translate3d(10px, 0, 0)", "translate3d(10px, 0, 0)"},
+		{"-0.5rem", "-0.5rem"},
+		{"h1 0.50", "h1 .5"},
+	}
+	for _, tt := range tests {
+		if got := minifyValue(tt.value); got != tt.want {
+			t.Errorf("minifyValue(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func minClass(selector string, props ...string) cssClass {
+	class := cssClass{Selector: selector}
+	for i := 0; i < len(props); i += 2 {
+		class.Props = append(class.Props, CSSProperty{Property: props[i], Value: props[i+1]})
+	}
+	return class
+}
+
+func TestMergeRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		classes []cssClass
+		want    [][]string
+	}{
+		{
+			"identical",
+			[]cssClass{minClass(".a", "width", "1rem"), minClass(".b", "width", "1.0rem")},
+			[][]string{{".a", ".b"}},
+		},
+		{
+			"unrelated rule between",
+			[]cssClass{
+				minClass(".a", "width", "1rem"),
+				minClass(".b", "height", "2rem"),
+				minClass(".c", "width", "1rem"),
+			},
+			[][]string{{".a", ".c"}, {".b"}},
+		},
+		{
+			"blocked by intervening property",
+			[]cssClass{
+				minClass(".a", "width", "1rem"),
+				minClass(".b", "width", "2rem"),
+				minClass(".c", "width", "1rem"),
+			},
+			[][]string{{".a"}, {".b"}, {".c"}},
+		},
+		{
+			"blocked by one of several properties",
+			[]cssClass{
+				minClass(".a", "margin-left", "1rem", "margin-right", "1rem"),
+				minClass(".b", "margin-right", "2rem"),
+				minClass(".c", "margin-left", "1rem", "margin-right", "1rem"),
+			},
+			[][]string{{".a"}, {".b"}, {".c"}},
+		},
+		{
+			"merged after a blocked rule",
+			[]cssClass{
+				minClass(".a", "width", "1rem"),
+				minClass(".b", "width", "2rem"),
+				minClass(".c", "width", "1rem"),
+				minClass(".d", "width", "1rem"),
+			},
+			[][]string{{".a"}, {".b"}, {".c", ".d"}},
+		},
+		{
+			"blocked by a longhand",
+			[]cssClass{
+				minClass(`.hover\:m-4:hover`, "margin", "1rem"),
+				minClass(`.hover\:ml-2:hover`, "margin-left", ".5rem"),
+				minClass(`.focus\:m-4:focus`, "margin", "1rem"),
+			},
+			[][]string{{`.hover\:m-4:hover`}, {`.hover\:ml-2:hover`}, {`.focus\:m-4:focus`}},
+		},
+		{
+			"blocked by a shorthand",
+			[]cssClass{
+				minClass(".a", "border-top-color", "#000"),
+				minClass(".b", "border", "0"),
+				minClass(".c", "border-top-color", "#000"),
+			},
+			[][]string{{".a"}, {".b"}, {".c"}},
+		},
+		{
+			"property with a common prefix",
+			[]cssClass{
+				minClass(".a", "margin", "1rem"),
+				minClass(".b", "marginal", "0"),
+				minClass(".c", "margin", "1rem"),
+			},
+			[][]string{{".a", ".c"}, {".b"}},
+		},
+		{
+			"different order of properties",
+			[]cssClass{
+				minClass(".a", "width", "1rem", "height", "1rem"),
+				minClass(".b", "height", "1rem", "width", "1rem"),
+			},
+			[][]string{{".a"}, {".b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := mergeRules(tt.classes)
+			var got [][]string
+			for _, rule := range rules {
+				got = append(got, rule.selectors)
+			}
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
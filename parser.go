@@ -1,8 +1,10 @@
 package csskit
 
 import (
+	"errors"
 	"fmt"
 	"slices"
+	"strings"
 )
 
 type ValueType int
@@ -12,6 +14,8 @@ const (
 	ValueFixed
 	ValueOneOf
 	ValueArbitrary
+	// ValueColor matches the name of any color in the configured palette.
+	ValueColor
 )
 
 type TokenMatcher struct {
@@ -21,8 +25,10 @@ type TokenMatcher struct {
 }
 
 type RawCSSClass struct {
-	Tokens []Token
-	Props  []CSSProperty
+	// Variants are the prefixes of the class, e.g. "md" in "md:w-4".
+	Variants []string
+	Tokens   []Token
+	Props    []CSSProperty
 }
 
 type CSSProperty struct {
@@ -30,17 +36,59 @@ type CSSProperty struct {
 	Value    string
 }
 
+var (
+	ErrNoMatchingPattern = errors.New("no matching pattern")
+	ErrUnknownVariant    = errors.New("unknown variant")
+)
+
+// Diagnostic describes a class-like candidate that could not be parsed.
+// Offset is the byte offset of the candidate within the lexer input.
+// Err wraps ErrNoMatchingPattern if no pattern matched the candidate,
+// otherwise its value couldn't be generated or its variants are invalid.
+type Diagnostic struct {
+	Class       string
+	Offset      int
+	Suggestions []string
+	Err         error
+}
+
 type Parser struct {
-	lexer *Lexer
+	lexer       *Lexer
+	config      *Config
+	diagnostics bool
+	unmatched   []Diagnostic
+	candidates  []int
 }
 
 func NewParser(lex *Lexer) *Parser {
-	return &Parser{lexer: lex}
+	return NewParserWithConfig(lex, defaultConfig)
+}
+
+func NewParserWithConfig(lex *Lexer, cfg *Config) *Parser {
+	return &Parser{lexer: lex, config: cfg}
+}
+
+// EnableDiagnostics makes the parser collect class-like candidates
+// that match no pattern, they can be retrieved with Unmatched.
+func (p *Parser) EnableDiagnostics() {
+	p.diagnostics = true
+}
+
+// Reset makes the parser start over on a new input, the candidates
+// collected for the previous input are discarded.
+func (p *Parser) Reset(input string) {
+	p.lexer.Reset(input)
+	p.unmatched = nil
+}
+
+func (p *Parser) Unmatched() []Diagnostic {
+	return p.unmatched
 }
 
 func (p *Parser) Parse() ([]RawCSSClass, error) {
 	var classes []RawCSSClass
 
+	var variants []Token
 	var tokens []Token
 	var prevTok Token
 	collecting := true
@@ -51,10 +99,7 @@ func (p *Parser) Parse() ([]RawCSSClass, error) {
 		if tok.Type == TokenEOF {
 			if len(tokens) > 0 {
 				if collecting && isValidLastToken(prevTok) {
-					class, err := p.parseClass(tokens)
-					if err == nil {
-						classes = append(classes, class)
-					}
+					classes = p.appendClass(classes, variants, tokens)
 				}
 			}
 			break
@@ -63,14 +108,12 @@ func (p *Parser) Parse() ([]RawCSSClass, error) {
 		if tok.Type == TokenSpace {
 			if len(tokens) > 0 {
 				if collecting && isValidLastToken(prevTok) {
-					class, err := p.parseClass(tokens)
-					if err == nil {
-						classes = append(classes, class)
-					}
+					classes = p.appendClass(classes, variants, tokens)
 				}
-				tokens = nil
-				prevTok = Token{}
 			}
+			variants = nil
+			tokens = nil
+			prevTok = Token{}
 			collecting = true
 			continue
 		}
@@ -96,6 +139,23 @@ func (p *Parser) Parse() ([]RawCSSClass, error) {
 				collecting = false
 				continue
 			}
+		case TokenColon:
+			// Keywords can only follow hyphens and numbers are always
+			// last, so the tokens form a hyphenated variant name.
+			if prevTok.Type != TokenKeyword {
+				collecting = false
+				continue
+			}
+			if collecting {
+				variants = append(variants, Token{
+					Type:   TokenKeyword,
+					Value:  joinTokens(tokens),
+					Offset: tokens[0].Offset,
+				})
+				tokens = nil
+				prevTok = tok
+			}
+			continue
 		case TokenGarbage:
 			collecting = false
 			continue
@@ -110,6 +170,94 @@ func (p *Parser) Parse() ([]RawCSSClass, error) {
 	return classes, nil
 }
 
+func (p *Parser) appendClass(classes []RawCSSClass, variants, tokens []Token) []RawCSSClass {
+	class, err := p.parseClass(tokens)
+	if err == nil {
+		class.Variants, err = p.parseVariants(variants)
+	}
+	if err == nil {
+		return append(classes, class)
+	}
+	if !p.diagnostics {
+		return classes
+	}
+	// A candidate that matched a pattern is a utility class for sure,
+	// the others are only reported if they look like one.
+	unmatched := errors.Is(err, ErrNoMatchingPattern)
+	if unmatched && !p.isClassLike(tokens) {
+		return classes
+	}
+	d := Diagnostic{
+		Class:  joinVariants(variants) + joinTokens(tokens),
+		Offset: tokens[0].Offset,
+		Err:    err,
+	}
+	if len(variants) > 0 {
+		d.Offset = variants[0].Offset
+	}
+	if unmatched || errors.Is(err, ErrUnknownVariant) {
+		d.Suggestions = p.suggest(variants, tokens)
+	}
+	p.unmatched = append(p.unmatched, d)
+	return classes
+}
+
+// parseVariants validates the variant prefixes of a class, see
+// Config.classifyVariants for the rules.
+func (p *Parser) parseVariants(variants []Token) ([]string, error) {
+	if len(variants) == 0 {
+		return nil, nil
+	}
+	names := make([]string, len(variants))
+	for i, v := range variants {
+		names[i] = v.Value
+	}
+	if _, err := p.config.classifyVariants(names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func joinVariants(variants []Token) string {
+	var sb strings.Builder
+	for _, v := range variants {
+		sb.WriteString(v.Value)
+		sb.WriteByte(':')
+	}
+	return sb.String()
+}
+
+// isClassLike reports whether tokens look like an attempt at a utility
+// class rather than an ordinary word: they either contain a number or
+// start with the leading keyword of an enabled pattern.
+func (p *Parser) isClassLike(tokens []Token) bool {
+	for _, tok := range tokens {
+		if tok.Type == TokenNumber {
+			return true
+		}
+	}
+	patterns := p.config.registry().patterns
+	for i := range patterns {
+		pattern := &patterns[i]
+		if !p.config.groupEnabled(pattern.Group) {
+			continue
+		}
+		first := pattern.Matchers[0]
+		if first.TokT == TokenKeyword && slices.Contains(first.Values, tokens[0].Value) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinTokens(tokens []Token) string {
+	var sb strings.Builder
+	for _, tok := range tokens {
+		sb.WriteString(tok.Value)
+	}
+	return sb.String()
+}
+
 func isValidLastToken(tok Token) bool {
 	switch tok.Type {
 	case TokenKeyword, TokenNumber, TokenUnit:
@@ -120,21 +268,26 @@ func isValidLastToken(tok Token) bool {
 }
 
 func (p *Parser) parseClass(tokens []Token) (RawCSSClass, error) {
-	for i := 0; i < classPatternCount; i++ {
-		pattern := &classPatterns[i]
-		if !matchPattern(pattern, tokens) {
+	reg := p.config.registry()
+	p.candidates = reg.index.lookup(p.candidates[:0], tokens)
+	for _, i := range p.candidates {
+		pattern := &reg.patterns[i]
+		if !p.config.groupEnabled(pattern.Group) {
+			continue
+		}
+		if !matchPattern(p.config, pattern, tokens) {
 			continue
 		}
-		props, err := pattern.Generate(tokens)
+		props, err := pattern.Generate(p.config, tokens)
 		if err != nil {
 			return RawCSSClass{}, fmt.Errorf("generation error: %v", err)
 		}
 		return RawCSSClass{Tokens: tokens, Props: props}, nil
 	}
-	return RawCSSClass{}, fmt.Errorf("no matching pattern for tokens: %v", tokens)
+	return RawCSSClass{}, fmt.Errorf("%w for tokens: %v", ErrNoMatchingPattern, tokens)
 }
 
-func matchPattern(pattern *ClassPattern, tokens []Token) bool {
+func matchPattern(cfg *Config, pattern *ClassPattern, tokens []Token) bool {
 	matcherCount := len(pattern.Matchers)
 	tokenCount := len(tokens)
 	lastMatcherType := pattern.Matchers[matcherCount-1].TokT
@@ -177,6 +330,10 @@ func matchPattern(pattern *ClassPattern, tokens []Token) bool {
 				return false
 			}
 		case ValueArbitrary:
+		case ValueColor:
+			if _, ok := cfg.shadeMap(tok.Value); !ok {
+				return false
+			}
 		}
 	}
 
@@ -0,0 +1,76 @@
+package csskit
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParserDiagnostics(t *testing.T) {
+	tests := []struct {
+		class       string
+		err         error
+		suggestions []string
+	}{
+		{"wx-4", ErrNoMatchingPattern, []string{"mx-4", "px-4", "w-4"}},
+		{"bg-blue-1000", nil, nil},
+		{"foo:w-4", ErrUnknownVariant, nil},
+		{"hovr:w-4", ErrUnknownVariant, []string{"hover:w-4"}},
+		{"md:sm:w-4", nil, nil},
+	}
+	for _, tt := range tests {
+		p := NewParser(NewLexer(tt.class))
+		p.EnableDiagnostics()
+		if _, err := p.Parse(); err != nil {
+			t.Fatal(err)
+		}
+		diags := p.Unmatched()
+		if len(diags) != 1 {
+			t.Errorf("%s: got %d diagnostics, want 1", tt.class, len(diags))
+			continue
+		}
+		d := diags[0]
+		if d.Class != tt.class || d.Offset != 0 {
+			t.Errorf("%s: got class %q at %d", tt.class, d.Class, d.Offset)
+		}
+		if d.Err == nil {
+			t.Errorf("%s: got no error", tt.class)
+		}
+		if tt.err != nil && !errors.Is(d.Err, tt.err) {
+			t.Errorf("%s: error = %v, want %v", tt.class, d.Err, tt.err)
+		}
+		if tt.err == nil && errors.Is(d.Err, ErrNoMatchingPattern) {
+			t.Errorf("%s: error = %v, want a generation or variant error", tt.class, d.Err)
+		}
+		if !slices.Equal(d.Suggestions, tt.suggestions) {
+			t.Errorf("%s: suggestions = %q, want %q", tt.class, d.Suggestions, tt.suggestions)
+		}
+	}
+}
+
+func TestParserDiagnosticsSkipWords(t *testing.T) {
+	p := NewParser(NewLexer("hello world text-align"))
+	p.EnableDiagnostics()
+	if _, err := p.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if diags := p.Unmatched(); len(diags) != 1 || diags[0].Class != "text-align" {
+		t.Errorf("got %+v, want only text-align", diags)
+	}
+}
+
+func TestParseVariants(t *testing.T) {
+	rcs, err := NewParser(NewLexer("md:hover:w-4 md: md:w-4: md::w-4 :w-4 w-4:md lg:xl:w-4 md:foo")).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rcs) != 1 {
+		t.Fatalf("parsed %d classes, want 1: %+v", len(rcs), rcs)
+	}
+	if want := []string{"md", "hover"}; !slices.Equal(rcs[0].Variants, want) {
+		t.Errorf("variants = %q, want %q", rcs[0].Variants, want)
+	}
+	if got := joinTokens(rcs[0].Tokens); got != "w-4" {
+		t.Errorf("tokens = %q, want %q", got, "w-4")
+	}
+}
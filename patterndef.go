@@ -0,0 +1,136 @@
+package csskit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	SlotNone   = ""
+	SlotSize   = "size"
+	SlotNumber = "number"
+	SlotColor  = "color"
+)
+
+// PatternDef declares a simple class pattern, e.g. "max-w-{size}":
+//
+//	{
+//	    "keywords": ["max", "w"],
+//	    "slot": "size",
+//	    "properties": ["max-width: {size}"]
+//	}
+//
+// The slot is the value following the keywords, one of "size" (a number
+// with an optional unit, converted like in "w-4"), "number", "color"
+// (a color name and shade, like in "bg-blue-500") or empty for none.
+// The properties can refer to the slot value with "{size}", "{number}"
+// or "{color}" respectively.
+type PatternDef struct {
+	Name       string   `json:"name"`
+	Group      string   `json:"group"`
+	Keywords   []string `json:"keywords"`
+	Slot       string   `json:"slot"`
+	Units      []string `json:"units"`
+	UnitReq    bool     `json:"unitRequired"`
+	Properties []string `json:"properties"`
+}
+
+// Pattern builds the class pattern described by the definition.
+func (d PatternDef) Pattern() (ClassPattern, error) {
+	if len(d.Keywords) == 0 {
+		return ClassPattern{}, errors.New("missing keywords")
+	}
+	if len(d.Properties) == 0 {
+		return ClassPattern{}, errors.New("missing properties")
+	}
+
+	cp := ClassPattern{
+		Name:    d.Name,
+		Group:   d.Group,
+		UnitReq: d.UnitReq,
+	}
+	if cp.Name == "" {
+		cp.Name = strings.Join(d.Keywords, "-")
+	}
+	if cp.Group == "" {
+		cp.Group = "custom"
+	}
+
+	for i, kw := range d.Keywords {
+		if !isKeyword(kw) {
+			return ClassPattern{}, fmt.Errorf("invalid keyword: %q", kw)
+		}
+		if i > 0 {
+			cp.Matchers = append(cp.Matchers, HyphenMatcher())
+		}
+		cp.Matchers = append(cp.Matchers, LiteralMatcher(kw))
+	}
+
+	if d.Slot != SlotSize && (len(d.Units) > 0 || d.UnitReq) {
+		return ClassPattern{}, errors.New("units are only allowed with the size slot")
+	}
+
+	switch d.Slot {
+	case SlotNone:
+	case SlotSize:
+		units := d.Units
+		if len(units) == 0 {
+			units = sizeUnits
+		}
+		for _, unit := range units {
+			if _, ok := unitOrder[unit]; !ok {
+				return ClassPattern{}, fmt.Errorf("unknown unit: %q", unit)
+			}
+		}
+		cp.Matchers = append(cp.Matchers, HyphenMatcher(), NumberMatcher(), UnitMatcher(units))
+	case SlotNumber:
+		cp.Matchers = append(cp.Matchers, HyphenMatcher(), NumberMatcher())
+	case SlotColor:
+		cp.Matchers = append(cp.Matchers, HyphenMatcher(), ColorMatcher(), HyphenMatcher(), NumberMatcher())
+	default:
+		return ClassPattern{}, fmt.Errorf("unknown slot: %q", d.Slot)
+	}
+
+	props := make([]CSSProperty, len(d.Properties))
+	for i, decl := range d.Properties {
+		prop, tmpl, ok := strings.Cut(decl, ":")
+		prop, tmpl = strings.TrimSpace(prop), strings.TrimSpace(tmpl)
+		if !ok || prop == "" || tmpl == "" {
+			return ClassPattern{}, fmt.Errorf("invalid property: %q", decl)
+		}
+		for _, slot := range []string{SlotSize, SlotNumber, SlotColor} {
+			if slot != d.Slot && strings.Contains(tmpl, "{"+slot+"}") {
+				return ClassPattern{}, fmt.Errorf("property %q refers to a missing {%s} slot", decl, slot)
+			}
+		}
+		props[i] = CSSProperty{Property: prop, Value: tmpl}
+	}
+
+	slot := d.Slot
+	cp.Generate = func(cfg *Config, tokens []Token) ([]CSSProperty, error) {
+		var val string
+		var err error
+		switch slot {
+		case SlotSize:
+			val, err = getSizeValue(cfg, tokens)
+		case SlotNumber:
+			val = tokens[len(tokens)-1].Value
+		case SlotColor:
+			val, err = getColorValue(cfg, tokens)
+		}
+		if err != nil {
+			return nil, err
+		}
+		out := slices.Clone(props)
+		if slot != SlotNone {
+			for i := range out {
+				out[i].Value = strings.ReplaceAll(out[i].Value, "{"+slot+"}", val)
+			}
+		}
+		return out, nil
+	}
+
+	return cp, nil
+}
@@ -0,0 +1,147 @@
+package csskit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// parsedProps parses input with cfg and returns the declarations of each
+// class as "class{property: value; ...}".
+func parsedProps(t *testing.T, cfg *Config, input string) []string {
+	t.Helper()
+	rcs, err := NewParserWithConfig(NewLexer(input), cfg).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, rc := range rcs {
+		var decls []string
+		for _, prop := range rc.Props {
+			decls = append(decls, prop.Property+": "+prop.Value)
+		}
+		got = append(got, fmt.Sprintf("%s{%s}", joinTokens(rc.Tokens), strings.Join(decls, "; ")))
+	}
+	return got
+}
+
+func TestPatternDefSlots(t *testing.T) {
+	tests := []struct {
+		name  string
+		def   PatternDef
+		input string
+		want  []string
+	}{
+		{
+			"size",
+			PatternDef{Keywords: []string{"gap", "x"}, Slot: SlotSize, Properties: []string{"column-gap: {size}"}},
+			"gap-x-4 gap-x-4px gap-x-50% gap-x-4em gap-x gap-x-red-500",
+			[]string{"gap-x-4{column-gap: 1.0000rem}", "gap-x-4px{column-gap: 4px}", "gap-x-50%{column-gap: 50%}"},
+		},
+		{
+			"size with units",
+			PatternDef{Keywords: []string{"inset"}, Slot: SlotSize, Units: []string{"px"}, Properties: []string{"inset: {size}"}},
+			"inset-2 inset-2px inset-2%",
+			[]string{"inset-2{inset: 0.5000rem}", "inset-2px{inset: 2px}"},
+		},
+		{
+			"size with a required unit",
+			PatternDef{Keywords: []string{"inset"}, Slot: SlotSize, Units: []string{"px", "vh"}, UnitReq: true, Properties: []string{"inset: {size}"}},
+			"inset-2 inset-2px inset-2vh inset-2%",
+			[]string{"inset-2px{inset: 2px}", "inset-2vh{inset: 2vh}"},
+		},
+		{
+			"number",
+			PatternDef{Keywords: []string{"layer"}, Slot: SlotNumber, Properties: []string{"z-index: {number}", "isolation: isolate"}},
+			"layer-10 layer-1.5 layer-10px layer",
+			[]string{"layer-10{z-index: 10; isolation: isolate}", "layer-1.5{z-index: 1.5; isolation: isolate}"},
+		},
+		{
+			"color",
+			PatternDef{Keywords: []string{"fill"}, Slot: SlotColor, Properties: []string{"fill: {color}"}},
+			"fill-red-500 fill-brand-500 fill-red fill-4",
+			[]string{"fill-red-500{fill: #ef4444}"},
+		},
+		{
+			"none",
+			PatternDef{Keywords: []string{"sr", "only"}, Properties: []string{"position: absolute", "width:1px"}},
+			"sr-only sr-only-4 sr",
+			[]string{"sr-only{position: absolute; width: 1px}"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Utilities = []PatternDef{tt.def}
+			if err := cfg.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if got := parsedProps(t, cfg, tt.input); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatternDefDefaults(t *testing.T) {
+	cp, err := PatternDef{Keywords: []string{"sr", "only"}, Properties: []string{"position: absolute"}}.Pattern()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Name != "sr-only" || cp.Group != "custom" {
+		t.Errorf("got name %q and group %q, want %q and %q", cp.Name, cp.Group, "sr-only", "custom")
+	}
+}
+
+func TestPatternDefErrors(t *testing.T) {
+	size := func(d PatternDef) PatternDef {
+		d.Keywords = []string{"inset"}
+		if d.Properties == nil {
+			d.Properties = []string{"inset: {size}"}
+		}
+		return d
+	}
+	tests := []struct {
+		name string
+		def  PatternDef
+		want string
+	}{
+		{"missing keywords", PatternDef{Properties: []string{"inset: 0"}}, "missing keywords"},
+		{"missing properties", PatternDef{Keywords: []string{"inset"}}, "missing properties"},
+		{"invalid keyword", PatternDef{Keywords: []string{"Inset"}, Properties: []string{"inset: 0"}}, `invalid keyword: "Inset"`},
+		{"keyword with a hyphen", PatternDef{Keywords: []string{"inset-x"}, Properties: []string{"inset: 0"}}, `invalid keyword: "inset-x"`},
+		{"units with a number slot", PatternDef{Keywords: []string{"layer"}, Slot: SlotNumber, Units: []string{"px"}, Properties: []string{"z-index: {number}"}}, "units are only allowed with the size slot"},
+		{"required unit without a slot", PatternDef{Keywords: []string{"layer"}, UnitReq: true, Properties: []string{"z-index: 1"}}, "units are only allowed with the size slot"},
+		{"unknown unit", size(PatternDef{Slot: SlotSize, Units: []string{"px", "em"}}), `unknown unit: "em"`},
+		{"unknown slot", size(PatternDef{Slot: "length"}), `unknown slot: "length"`},
+		{"invalid property", size(PatternDef{Slot: SlotSize, Properties: []string{"inset"}}), `invalid property: "inset"`},
+		{"empty value", size(PatternDef{Slot: SlotSize, Properties: []string{"inset: "}}), `invalid property: "inset: "`},
+		{"color placeholder with a size slot", size(PatternDef{Slot: SlotSize, Properties: []string{"color: {color}"}}), "refers to a missing {color} slot"},
+		{"size placeholder without a slot", size(PatternDef{Properties: []string{"inset: {size}"}}), "refers to a missing {size} slot"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.def.Pattern()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Pattern() error = %v, want %q", err, tt.want)
+			}
+
+			cfg := DefaultConfig()
+			cfg.Utilities = []PatternDef{tt.def}
+			if err := cfg.Validate(); err == nil || !strings.HasPrefix(err.Error(), "utilities[0]: ") {
+				t.Errorf("Validate() error = %v, want a utilities[0] error", err)
+			}
+		})
+	}
+}
+
+func TestValidateDuplicateUtilities(t *testing.T) {
+	def := PatternDef{Keywords: []string{"sr", "only"}, Properties: []string{"position: absolute"}}
+	cfg := DefaultConfig()
+	cfg.Utilities = []PatternDef{def, def}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), `utilities[1]: pattern "sr-only" already registered`) {
+		t.Errorf("Validate() error = %v, want a duplicate pattern error", err)
+	}
+}
@@ -2,51 +2,19 @@ package csskit
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 )
 
-const (
-	ColorSlate   = "slate"
-	ColorGray    = "gray"
-	ColorZinc    = "zinc"
-	ColorNeutral = "neutral"
-	ColorStone   = "stone"
-	ColorRed     = "red"
-	ColorOrange  = "orange"
-	ColorAmber   = "amber"
-	ColorYellow  = "yellow"
-	ColorLime    = "lime"
-	ColorGreen   = "green"
-	ColorEmerald = "emerald"
-	ColorTeal    = "teal"
-	ColorCyan    = "cyan"
-	ColorSky     = "sky"
-	ColorBlue    = "blue"
-	ColorIndigo  = "indigo"
-	ColorViolet  = "violet"
-	ColorPurple  = "purple"
-	ColorFuchsia = "fuchsia"
-	ColorPink    = "pink"
-	ColorRose    = "rose"
-)
-
-var allColors = []string{
-	ColorSlate, ColorGray, ColorZinc, ColorNeutral,
-	ColorStone, ColorRed, ColorOrange, ColorAmber,
-	ColorYellow, ColorLime, ColorGreen, ColorEmerald,
-	ColorTeal, ColorCyan, ColorSky, ColorBlue,
-	ColorIndigo, ColorViolet, ColorPurple, ColorFuchsia,
-	ColorPink, ColorRose,
-}
-
 type ClassPattern struct {
 	Name     string
+	Group    string
 	Matchers []TokenMatcher
 	UnitReq  bool
-	Generate func(tokens []Token) ([]CSSProperty, error)
+	Generate func(cfg *Config, tokens []Token) ([]CSSProperty, error)
 }
 
-func literalMatcher(l string) TokenMatcher {
+func LiteralMatcher(l string) TokenMatcher {
 	return TokenMatcher{
 		TokT:   TokenKeyword,
 		ValT:   ValueFixed,
@@ -54,7 +22,7 @@ func literalMatcher(l string) TokenMatcher {
 	}
 }
 
-func numberMatcher() TokenMatcher {
+func NumberMatcher() TokenMatcher {
 	return TokenMatcher{
 		TokT:   TokenNumber,
 		ValT:   ValueArbitrary,
@@ -62,7 +30,7 @@ func numberMatcher() TokenMatcher {
 	}
 }
 
-func unitMatcher(units []string) TokenMatcher {
+func UnitMatcher(units []string) TokenMatcher {
 	return TokenMatcher{
 		TokT:   TokenUnit,
 		ValT:   ValueOneOf,
@@ -70,7 +38,7 @@ func unitMatcher(units []string) TokenMatcher {
 	}
 }
 
-func hyphenMatcher() TokenMatcher {
+func HyphenMatcher() TokenMatcher {
 	return TokenMatcher{
 		TokT:   TokenHyphen,
 		ValT:   ValueFixed,
@@ -78,15 +46,15 @@ func hyphenMatcher() TokenMatcher {
 	}
 }
 
-func colorMatcher() TokenMatcher {
+func ColorMatcher() TokenMatcher {
 	return TokenMatcher{
 		TokT:   TokenKeyword,
-		ValT:   ValueOneOf,
-		Values: allColors,
+		ValT:   ValueColor,
+		Values: AllColorNames,
 	}
 }
 
-func getSizeValue(tokens []Token) (string, error) {
+func getSizeValue(cfg *Config, tokens []Token) (string, error) {
 	tokenCount := len(tokens)
 	lastToken := tokens[tokenCount-1]
 
@@ -100,30 +68,121 @@ func getSizeValue(tokens []Token) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		return fmt.Sprintf("%.4frem", fl64/4.0), nil
+		return fmt.Sprintf("%.4frem", fl64/cfg.RemBase), nil
 	default:
 		panic(fmt.Errorf("number token expected: %v", tokens))
 	}
 }
 
-var classPatternCount int
+func getColorValue(cfg *Config, tokens []Token) (string, error) {
+	tokenCount := len(tokens)
+	name := tokens[tokenCount-3].Value
+	shade, err := strconv.ParseFloat(tokens[tokenCount-1].Value, 64)
+	if err != nil {
+		return "", err
+	}
+	shadeMap, ok := cfg.shadeMap(name)
+	if !ok {
+		return "", fmt.Errorf("unknown color name: %s", name)
+	}
+	col, err := getColor(shadeMap, shade)
+	if err != nil {
+		return "", err
+	}
+	return formatColor(col), nil
+}
+
+var sizeUnits = []string{"px", "%", "vw", "vh"}
 
-func init() {
-	classPatternCount = len(classPatterns)
+func sizePattern(name, group, literal string, properties ...string) ClassPattern {
+	return ClassPattern{
+		Name:  name,
+		Group: group,
+		Matchers: []TokenMatcher{
+			LiteralMatcher(literal),
+			HyphenMatcher(),
+			NumberMatcher(),
+			UnitMatcher(sizeUnits),
+		},
+		UnitReq: false,
+		Generate: func(cfg *Config, tokens []Token) ([]CSSProperty, error) {
+			val, err := getSizeValue(cfg, tokens)
+			if err != nil {
+				return nil, err
+			}
+			props := make([]CSSProperty, len(properties))
+			for i, prop := range properties {
+				props[i] = CSSProperty{Property: prop, Value: val}
+			}
+			return props, nil
+		},
+	}
 }
 
-var classPatterns = []ClassPattern{
+// spacingPatterns returns the patterns for a spacing property, e.g. "m-4",
+// "mx-4" and "mt-4" for margin. The axis and side variants are formed
+// by appending one of x, y, t, r, b or l to the prefix.
+func spacingPatterns(name, prefix, property string) []ClassPattern {
+	return []ClassPattern{
+		sizePattern(name, "spacing", prefix, property),
+		sizePattern(name+"X", "spacing", prefix+"x", property+"-left", property+"-right"),
+		sizePattern(name+"Y", "spacing", prefix+"y", property+"-top", property+"-bottom"),
+		sizePattern(name+"Top", "spacing", prefix+"t", property+"-top"),
+		sizePattern(name+"Right", "spacing", prefix+"r", property+"-right"),
+		sizePattern(name+"Bottom", "spacing", prefix+"b", property+"-bottom"),
+		sizePattern(name+"Left", "spacing", prefix+"l", property+"-left"),
+	}
+}
+
+// colorPattern returns a pattern such as "bg-blue-500",
+// the shade may be any number within the range of defined shades.
+func colorPattern(name, literal, property string) ClassPattern {
+	return ClassPattern{
+		Name:  name,
+		Group: "colors",
+		Matchers: []TokenMatcher{
+			LiteralMatcher(literal),
+			HyphenMatcher(),
+			ColorMatcher(),
+			HyphenMatcher(),
+			NumberMatcher(),
+		},
+		UnitReq: false,
+		Generate: func(cfg *Config, tokens []Token) ([]CSSProperty, error) {
+			val, err := getColorValue(cfg, tokens)
+			if err != nil {
+				return nil, err
+			}
+			return []CSSProperty{
+				{Property: property, Value: val},
+			}, nil
+		},
+	}
+}
+
+var classPatterns = slices.Concat(baseClassPatterns,
+	spacingPatterns("Margin", "m", "margin"),
+	spacingPatterns("Padding", "p", "padding"),
+	[]ClassPattern{
+		colorPattern("BackgroundColor", "bg", "background-color"),
+		colorPattern("TextColor", "text", "color"),
+		colorPattern("BorderColor", "border", "border-color"),
+	},
+)
+
+var baseClassPatterns = []ClassPattern{
 	{
-		Name: "Width",
+		Name:  "Width",
+		Group: "sizing",
 		Matchers: []TokenMatcher{
-			literalMatcher("w"),
-			hyphenMatcher(),
-			numberMatcher(),
-			unitMatcher([]string{"px", "%", "vw", "vh"}),
+			LiteralMatcher("w"),
+			HyphenMatcher(),
+			NumberMatcher(),
+			UnitMatcher(sizeUnits),
 		},
 		UnitReq: false,
-		Generate: func(tokens []Token) ([]CSSProperty, error) {
-			val, err := getSizeValue(tokens)
+		Generate: func(cfg *Config, tokens []Token) ([]CSSProperty, error) {
+			val, err := getSizeValue(cfg, tokens)
 			if err != nil {
 				return nil, err
 			}
@@ -133,4 +192,3 @@ var classPatterns = []ClassPattern{
 		},
 	},
 }
-
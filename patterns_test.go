@@ -0,0 +1,106 @@
+package csskit
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSpacingPatterns(t *testing.T) {
+	got := parsedProps(t, defaultConfig, "m-4 mx-2 my-1 mt-1 mr-1 mb-1 ml-1 p-4px px-4px py-50% pt-1vw")
+	want := []string{
+		"m-4{margin: 1.0000rem}",
+		"mx-2{margin-left: 0.5000rem; margin-right: 0.5000rem}",
+		"my-1{margin-top: 0.2500rem; margin-bottom: 0.2500rem}",
+		"mt-1{margin-top: 0.2500rem}",
+		"mr-1{margin-right: 0.2500rem}",
+		"mb-1{margin-bottom: 0.2500rem}",
+		"ml-1{margin-left: 0.2500rem}",
+		"p-4px{padding: 4px}",
+		"px-4px{padding-left: 4px; padding-right: 4px}",
+		"py-50%{padding-top: 50%; padding-bottom: 50%}",
+		"pt-1vw{padding-top: 1vw}",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLexUnitLikeKeywords(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []Token
+	}{
+		{"px-4px", []Token{
+			{Type: TokenKeyword, Value: "px"}, {Type: TokenHyphen, Value: "-"},
+			{Type: TokenNumber, Value: "4"}, {Type: TokenUnit, Value: "px"},
+		}},
+		{"p-4px", []Token{
+			{Type: TokenKeyword, Value: "p"}, {Type: TokenHyphen, Value: "-"},
+			{Type: TokenNumber, Value: "4"}, {Type: TokenUnit, Value: "px"},
+		}},
+		{"px-4", []Token{
+			{Type: TokenKeyword, Value: "px"}, {Type: TokenHyphen, Value: "-"},
+			{Type: TokenNumber, Value: "4"},
+		}},
+	}
+	for _, tt := range tests {
+		lex := NewLexer(tt.input)
+		var got []Token
+		for tok := lex.NextToken(); tok.Type != TokenEOF; tok = lex.NextToken() {
+			got = append(got, Token{Type: tok.Type, Value: tok.Value})
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestColorPatterns(t *testing.T) {
+	got := parsedProps(t, defaultConfig, "bg-blue-500 bg-blue-550 text-red-50 border-gray-950 bg-blue-1000 bg-blue-25 bg-navy-500")
+	want := []string{
+		"bg-blue-500{background-color: #3b82f6}",
+		"bg-blue-550{background-color: #3072f0}",
+		"text-red-50{color: #fef2f2}",
+		"border-gray-950{border-color: #030712}",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestColorShadeOutOfBounds(t *testing.T) {
+	p := NewParser(NewLexer("bg-blue-1000"))
+	p.EnableDiagnostics()
+	if _, err := p.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	diags := p.Unmatched()
+	if len(diags) != 1 || errors.Is(diags[0].Err, ErrNoMatchingPattern) ||
+		!strings.Contains(diags[0].Err.Error(), "color shade out of bounds (50-950): 1000") {
+		t.Errorf("got %+v, want a shade out of bounds error", diags)
+	}
+}
+
+func TestCustomColorShades(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Colors = map[string]map[int]string{
+		"brand": {100: "#000000", 900: "#ffffff"},
+		// A custom color replaces the built-in one of the same name.
+		"blue": {500: "#123456"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	got := parsedProps(t, cfg, "bg-brand-100 bg-brand-500 text-brand-900 bg-brand-50 bg-brand-950 bg-blue-500 bg-blue-600")
+	want := []string{
+		"bg-brand-100{background-color: #000000}",
+		"bg-brand-500{background-color: #7f7f7f}",
+		"text-brand-900{color: #ffffff}",
+		"bg-blue-500{background-color: #123456}",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+package csskit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Registry holds the class patterns available to a parser. Patterns are
+// tried in the order they were registered. A registry must not be modified
+// while it's in use by a parser.
+type Registry struct {
+	patterns []ClassPattern
+	names    map[string]struct{}
+	index    patternIndex
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]struct{})}
+}
+
+// DefaultRegistry returns a new registry with the built-in patterns,
+// custom patterns can be registered on top of them.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, cp := range classPatterns {
+		r.add(cp)
+	}
+	return r
+}
+
+func (r *Registry) clone() *Registry {
+	c := NewRegistry()
+	for _, cp := range r.patterns {
+		c.add(cp)
+	}
+	return c
+}
+
+func (r *Registry) add(cp ClassPattern) {
+	r.patterns = append(r.patterns, cp)
+	r.names[cp.Name] = struct{}{}
+	r.index.insert(len(r.patterns)-1, &r.patterns[len(r.patterns)-1])
+}
+
+var defaultRegistry = DefaultRegistry()
+
+func (r *Registry) Register(cp ClassPattern) error {
+	if err := validatePattern(cp); err != nil {
+		return fmt.Errorf("pattern %q: %v", cp.Name, err)
+	}
+	if r.names == nil {
+		r.names = make(map[string]struct{})
+	}
+	if _, ok := r.names[cp.Name]; ok {
+		return fmt.Errorf("pattern %q already registered", cp.Name)
+	}
+	r.add(cp)
+	return nil
+}
+
+// Patterns returns the registered patterns in the order they're tried.
+func (r *Registry) Patterns() []ClassPattern {
+	return slices.Clone(r.patterns)
+}
+
+func (r *Registry) hasGroup(group string) bool {
+	return slices.ContainsFunc(r.patterns, func(cp ClassPattern) bool {
+		return cp.Group == group
+	})
+}
+
+// validatePattern checks that the matchers follow the class grammar:
+// a keyword, followed by hyphen separated keywords, optionally ending
+// with a number and a unit.
+func validatePattern(cp ClassPattern) error {
+	if cp.Name == "" {
+		return errors.New("missing name")
+	}
+	if cp.Generate == nil {
+		return errors.New("missing Generate function")
+	}
+	if len(cp.Matchers) == 0 {
+		return errors.New("missing matchers")
+	}
+
+	var prev TokenType
+	for i, m := range cp.Matchers {
+		valid := false
+		switch m.TokT {
+		case TokenKeyword:
+			valid = i == 0 || prev == TokenHyphen
+		case TokenHyphen:
+			valid = prev == TokenKeyword
+		case TokenNumber:
+			valid = prev == TokenHyphen
+		case TokenUnit:
+			valid = prev == TokenNumber && i == len(cp.Matchers)-1
+		}
+		if !valid {
+			return fmt.Errorf("unexpected %s matcher at position %d", GetTokenTypeName(m.TokT), i)
+		}
+		switch m.ValT {
+		case ValueFixed:
+			if len(m.Values) != 1 {
+				return fmt.Errorf("fixed matcher at position %d needs exactly one value", i)
+			}
+		case ValueOneOf:
+			if len(m.Values) == 0 {
+				return fmt.Errorf("one-of matcher at position %d needs values", i)
+			}
+		case ValueArbitrary, ValueColor:
+		default:
+			return fmt.Errorf("invalid value type at position %d", i)
+		}
+		if m.TokT == TokenUnit && m.ValT != ValueArbitrary {
+			for _, unit := range m.Values {
+				if _, ok := unitOrder[unit]; !ok {
+					return fmt.Errorf("unknown unit at position %d: %q", i, unit)
+				}
+			}
+		}
+		prev = m.TokT
+	}
+	if prev == TokenHyphen {
+		return errors.New("matchers can't end with a hyphen")
+	}
+	if cp.UnitReq && prev != TokenUnit {
+		return errors.New("unit required without a unit matcher")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package csskit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func elevationPattern(unit TokenMatcher) ClassPattern {
+	return ClassPattern{
+		Name:     "elevation",
+		Matchers: []TokenMatcher{LiteralMatcher("elev"), HyphenMatcher(), NumberMatcher(), unit},
+		Generate: func(cfg *Config, tokens []Token) ([]CSSProperty, error) {
+			return []CSSProperty{{Property: "--elevation", Value: joinTokens(tokens[2:])}}, nil
+		},
+	}
+}
+
+func TestRegisterRejectsUnknownUnits(t *testing.T) {
+	reg := DefaultRegistry()
+	err := reg.Register(elevationPattern(UnitMatcher([]string{"em", "rem"})))
+	if err == nil || !strings.Contains(err.Error(), `unknown unit at position 3: "em"`) {
+		t.Fatalf("Register() error = %v, want an unknown unit error", err)
+	}
+	if err := reg.Register(elevationPattern(UnitMatcher([]string{"px", "%"}))); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+}
+
+func TestGenerateArbitraryUnits(t *testing.T) {
+	reg := DefaultRegistry()
+	err := reg.Register(elevationPattern(TokenMatcher{TokT: TokenUnit, ValT: ValueArbitrary}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	cfg.Registry = reg
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	rcs, err := NewParserWithConfig(NewLexer("elev-3rem elev-3em elev-3px elev-3"), cfg).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rcs) != 4 {
+		t.Fatalf("parsed %d classes, want 4", len(rcs))
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateCSSWithConfig(&buf, rcs, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// Known units come first, the rest follow in string order.
+	want := []string{".elev-3 ", ".elev-3px ", ".elev-3em ", ".elev-3rem "}
+	css := buf.String()
+	last := -1
+	for _, sel := range want {
+		i := strings.Index(css, sel)
+		if i <= last {
+			t.Fatalf("%q is out of order in:\n%s", sel, css)
+		}
+		last = i
+	}
+}
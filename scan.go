@@ -0,0 +1,142 @@
+package csskit
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/igormichalak/csskit/extract"
+)
+
+type ScanOptions struct {
+	// Workers is the number of files scanned in parallel,
+	// GOMAXPROCS is used if it is not positive.
+	Workers int
+	// Diagnostics enables the collection of class-like candidates
+	// that can't be parsed. Only the values of class attributes are
+	// diagnosed, and the classes matching Config.IgnoreUnknown are left out.
+	Diagnostics bool
+}
+
+// FileDiagnostic is a Diagnostic located in the scanned source.
+type FileDiagnostic struct {
+	Pos         extract.Position
+	Class       string
+	Suggestions []string
+	Err         error
+}
+
+// FileResult holds the distinct classes of a single source in the order
+// of their first occurrence. Err is set if the source could not be fully
+// extracted, the classes found before the error are kept.
+type FileResult struct {
+	Path        string
+	Classes     []RawCSSClass
+	Diagnostics []FileDiagnostic
+	Err         error
+}
+
+// ScanFiles extracts and parses the files at paths in parallel. The
+// results are in the order of paths regardless of scheduling, so merging
+// them in order gives the same classes, diagnostics and errors each time.
+func ScanFiles(cfg *Config, paths []string, opts ScanOptions) []FileResult {
+	results := make([]FileResult, len(paths))
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(paths))
+
+	extractOpts := cfg.ExtractOptions()
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				s := newScanner(cfg, opts.Diagnostics)
+				err := extract.WalkFileWithOptions(paths[i], extractOpts, s.scanLiteral)
+				results[i] = s.result(paths[i], err)
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// ExtractOptions returns the options for extracting literals
+// from the sources according to the config.
+func (c *Config) ExtractOptions() extract.Options {
+	var opts extract.Options
+	if len(c.TemplateDelims) == 2 {
+		opts.TemplateDelims = extract.Delims{Left: c.TemplateDelims[0], Right: c.TemplateDelims[1]}
+	}
+	for _, attr := range c.ClassAttributes {
+		opts.ClassAttrs = append(opts.ClassAttrs, extract.ClassAttr{Name: attr.Name, Syntax: attr.Syntax})
+	}
+	return opts
+}
+
+// ScanLiterals parses literals that were already extracted, e.g. the
+// classes of the safelist. The Workers option is ignored.
+func ScanLiterals(cfg *Config, lits []extract.Literal, opts ScanOptions) FileResult {
+	s := newScanner(cfg, opts.Diagnostics)
+	for _, lit := range lits {
+		if err := s.scanLiteral(lit); err != nil {
+			return s.result("", err)
+		}
+	}
+	return s.result("", nil)
+}
+
+type scanner struct {
+	config      *Config
+	parser      *Parser
+	diagnostics bool
+	classes     ClassSet
+	diags       []FileDiagnostic
+}
+
+func newScanner(cfg *Config, diagnostics bool) *scanner {
+	p := NewParserWithConfig(NewLexer(""), cfg)
+	return &scanner{config: cfg, parser: p, diagnostics: diagnostics}
+}
+
+func (s *scanner) scanLiteral(lit extract.Literal) error {
+	// String literals in code may hold any text, such as "utf-8",
+	// so only the values of class attributes are diagnosed.
+	s.parser.diagnostics = s.diagnostics && lit.Kind == extract.KindClassValue
+	s.parser.Reset(lit.Value)
+	rcs, err := s.parser.Parse()
+	if err != nil {
+		return err
+	}
+	s.classes.Add(rcs...)
+	for _, d := range s.parser.Unmatched() {
+		if s.config.ignoredUnknown(d.Class) {
+			continue
+		}
+		s.diags = append(s.diags, FileDiagnostic{
+			Pos:         lit.PositionAt(d.Offset),
+			Class:       d.Class,
+			Suggestions: d.Suggestions,
+			Err:         d.Err,
+		})
+	}
+	return nil
+}
+
+func (s *scanner) result(path string, err error) FileResult {
+	return FileResult{
+		Path:        path,
+		Classes:     s.classes.Classes(),
+		Diagnostics: s.diags,
+		Err:         err,
+	}
+}
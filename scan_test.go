@@ -0,0 +1,139 @@
+package csskit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/igormichalak/csskit/extract"
+)
+
+func writeSources(t *testing.T, files map[string]string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var paths []string
+	for name, content := range files {
+		fp := filepath.Join(dir, name)
+		if err := os.WriteFile(fp, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, fp)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func diagnosedClasses(results ...FileResult) []string {
+	var classes []string
+	for _, res := range results {
+		for _, d := range res.Diagnostics {
+			classes = append(classes, d.Class)
+		}
+	}
+	return classes
+}
+
+func TestScanFilesDiagnostics(t *testing.T) {
+	paths := writeSources(t, map[string]string{
+		"a.html": `<meta charset="utf-8">` +
+			`<p class="wdth-4 w-10pc md:bg-blu-500 w-4 text-align">` +
+			`<script>el.className = "wx-4"; const enc = "utf-8";</script>`,
+		"b.js": `const enc = "utf-8", align = "text-align", sizing = 'border-box', pad = "px-4z";` +
+			"const cls = `w-${n} mt-1x`;",
+		"c.gohtml": `{{ $x := "utf-8" }}<p class="wx-8 {{ if .A }}mx-2{{ end }}">`,
+	})
+
+	results := ScanFiles(defaultConfig, paths, ScanOptions{Diagnostics: true})
+	for _, res := range results {
+		if res.Err != nil {
+			t.Fatalf("%s: %v", res.Path, res.Err)
+		}
+	}
+
+	want := []string{"wdth-4", "w-10pc", "md:bg-blu-500", "text-align", "wx-8"}
+	if got := diagnosedClasses(results...); !slices.Equal(got, want) {
+		t.Errorf("diagnosed %q, want %q", got, want)
+	}
+}
+
+func TestScanLiteralsIgnoreUnknown(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.IgnoreUnknown = []string{"col-*", "btn-[0-9]"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	lits := []extract.Literal{
+		{Value: "col-6 md:col-12 btn-2 btn-22 wx-4"},
+		{Value: "wy-4", Kind: extract.KindString},
+	}
+	res := ScanLiterals(cfg, lits, ScanOptions{Diagnostics: true})
+
+	want := []string{"btn-22", "wx-4"}
+	if got := diagnosedClasses(res); !slices.Equal(got, want) {
+		t.Errorf("diagnosed %q, want %q", got, want)
+	}
+}
+
+func TestValidateIgnoreUnknown(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.IgnoreUnknown = []string{"col-["}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() accepted a malformed ignoreUnknown pattern")
+	}
+}
+
+// scanSummary merges the results of ScanFiles in order, the way the
+// command does, and renders them as text.
+func scanSummary(t *testing.T, cfg *Config, results []FileResult) string {
+	t.Helper()
+	var sb strings.Builder
+	var classes ClassSet
+	for _, res := range results {
+		classes.Add(res.Classes...)
+		for _, d := range res.Diagnostics {
+			fmt.Fprintf(&sb, "%s: %s %q %v\n", d.Pos, d.Class, d.Suggestions, d.Err)
+		}
+		if res.Err != nil {
+			fmt.Fprintf(&sb, "%s: %v\n", res.Path, res.Err)
+		}
+	}
+	if err := GenerateCSSWithConfig(&sb, classes.Classes(), cfg); err != nil {
+		t.Fatal(err)
+	}
+	return sb.String()
+}
+
+func TestScanFilesDeterministic(t *testing.T) {
+	files := make(map[string]string)
+	for i := range 40 {
+		name := fmt.Sprintf("f%02d", i)
+		switch i % 4 {
+		case 0:
+			files[name+".html"] = fmt.Sprintf(`<p class="w-%d md:p-%d wx-%d hover:m-%d">`, i, i%5, i, i%3)
+		case 1:
+			files[name+".js"] = fmt.Sprintf(`el.className = "bg-red-%d00 mx-%d"; x = "w-%d"`, i%9+1, i%4, i%6)
+		case 2:
+			files[name+".gohtml"] = fmt.Sprintf(`<p class="m-%d {{ if .A }}dark:w-%d{{ end }} bg-blu-500">{{ "p-%d" }}`, i%7, i, i%2)
+		case 3:
+			files[name+".js"] = fmt.Sprintf(`x = "w-%d m-1"; y = "unterminated`, i%8)
+		}
+	}
+	paths := writeSources(t, files)
+
+	want := scanSummary(t, defaultConfig, ScanFiles(defaultConfig, paths, ScanOptions{Workers: 1, Diagnostics: true}))
+	if !strings.Contains(want, "unterminated string") || !strings.Contains(want, "wx-") {
+		t.Fatalf("expected errors and diagnostics in:\n%s", want)
+	}
+	for range 10 {
+		for _, workers := range []int{1, 8} {
+			results := ScanFiles(defaultConfig, paths, ScanOptions{Workers: workers, Diagnostics: true})
+			if got := scanSummary(t, defaultConfig, results); got != want {
+				t.Fatalf("output with %d workers differs:\n%s\nwant:\n%s", workers, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package csskit
+
+import (
+	"slices"
+	"strings"
+)
+
+const maxSuggestions = 3
+
+// unitAliases maps common misspellings of units
+// that are too far from the real unit in edit distance.
+var unitAliases = map[string]string{
+	"pc":      "%",
+	"pct":     "%",
+	"percent": "%",
+}
+
+type suggestion struct {
+	class string
+	cost  int
+}
+
+// suggest returns the valid class names closest to the variants
+// and tokens. Only the suggestions requiring the fewest edits are
+// returned, ordered alphabetically.
+func (p *Parser) suggest(variants, tokens []Token) []string {
+	repaired, variantCost, ok := repairVariants(p.config, variants)
+	if !ok {
+		return nil
+	}
+	if _, err := p.parseVariants(repaired); err != nil {
+		return nil
+	}
+	prefix := joinVariants(repaired)
+	input := joinVariants(variants) + joinTokens(tokens)
+
+	var found []suggestion
+	patterns := p.config.registry().patterns
+	for i := range patterns {
+		pattern := &patterns[i]
+		if !p.config.groupEnabled(pattern.Group) {
+			continue
+		}
+		if class, cost, ok := repairClass(p.config, pattern, tokens); ok && prefix+class != input {
+			found = append(found, suggestion{class: prefix + class, cost: variantCost + cost})
+		}
+	}
+
+	slices.SortFunc(found, func(a, b suggestion) int {
+		if a.cost != b.cost {
+			return a.cost - b.cost
+		}
+		return compareStrings(a.class, b.class)
+	})
+
+	var classes []string
+	for _, s := range found {
+		if len(classes) == maxSuggestions || s.cost > found[0].cost {
+			break
+		}
+		if !slices.Contains(classes, s.class) {
+			classes = append(classes, s.class)
+		}
+	}
+	return classes
+}
+
+// repairVariants replaces every variant with the closest known one.
+func repairVariants(cfg *Config, variants []Token) ([]Token, int, bool) {
+	names := cfg.variantNames()
+	repaired := make([]Token, len(variants))
+	cost := 0
+	for i, v := range variants {
+		best, dist := closestValue(v.Value, names)
+		if dist == -1 || dist > maxEdits(v.Value, best) {
+			return nil, 0, false
+		}
+		repaired[i] = Token{Type: v.Type, Value: best, Offset: v.Offset}
+		cost += dist
+	}
+	return repaired, cost, true
+}
+
+// repairClass replaces every keyword and unit of tokens with the closest
+// value accepted by the pattern. It fails if the structure of tokens
+// doesn't fit the pattern or a replacement is too far from the original.
+func repairClass(cfg *Config, pattern *ClassPattern, tokens []Token) (string, int, bool) {
+	matcherCount := len(pattern.Matchers)
+	lastMatcher := pattern.Matchers[matcherCount-1]
+
+	switch len(tokens) {
+	case matcherCount:
+	case matcherCount - 1:
+		if lastMatcher.TokT != TokenUnit || pattern.UnitReq {
+			return "", 0, false
+		}
+	default:
+		return "", 0, false
+	}
+
+	var sb strings.Builder
+	cost := 0
+
+	for i, tok := range tokens {
+		matcher := pattern.Matchers[i]
+		if tok.Type != matcher.TokT {
+			return "", 0, false
+		}
+		if matcher.ValT == ValueArbitrary {
+			sb.WriteString(tok.Value)
+			continue
+		}
+
+		values := matcher.Values
+		if matcher.ValT == ValueColor {
+			values = cfg.colorNames()
+		}
+		best, dist := closestValue(tok.Value, values)
+		if matcher.TokT == TokenUnit {
+			if alias, ok := unitAliases[tok.Value]; ok && slices.Contains(matcher.Values, alias) {
+				best, dist = alias, 0
+			}
+		}
+		if dist > maxEdits(tok.Value, best) {
+			return "", 0, false
+		}
+		sb.WriteString(best)
+		cost += dist
+	}
+
+	return sb.String(), cost, true
+}
+
+func closestValue(s string, values []string) (string, int) {
+	best := ""
+	bestDist := -1
+	for _, v := range values {
+		d := editDistance(s, v)
+		if bestDist == -1 || d < bestDist {
+			best, bestDist = v, d
+		}
+	}
+	return best, bestDist
+}
+
+func maxEdits(a, b string) int {
+	if max(len(a), len(b)) <= 4 {
+		return 1
+	}
+	return 2
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
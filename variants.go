@@ -0,0 +1,127 @@
+package csskit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type StateVariant struct {
+	Name   string
+	Pseudo string
+}
+
+// StateVariants are ordered by their precedence, rules with
+// the later variants are emitted after the earlier ones.
+var StateVariants = []StateVariant{
+	{Name: "first", Pseudo: ":first-child"},
+	{Name: "last", Pseudo: ":last-child"},
+	{Name: "odd", Pseudo: ":nth-child(odd)"},
+	{Name: "even", Pseudo: ":nth-child(even)"},
+	{Name: "hover", Pseudo: ":hover"},
+	{Name: "focus", Pseudo: ":focus"},
+	{Name: "focus-visible", Pseudo: ":focus-visible"},
+	{Name: "active", Pseudo: ":active"},
+	{Name: "disabled", Pseudo: ":disabled"},
+}
+
+func stateIndex(name string) int {
+	return slices.IndexFunc(StateVariants, func(sv StateVariant) bool {
+		return sv.Name == name
+	})
+}
+
+const DarkVariant = "dark"
+
+type variantSet struct {
+	breakpoint int
+	dark       bool
+	// states holds the indices into StateVariants in the written order.
+	states []int
+}
+
+// selector returns the selector of the class with the given escaped
+// identifier, the pseudo-classes of the states are in the written order.
+func (vs variantSet) selector(cfg *Config, identifier string) string {
+	var sb strings.Builder
+	if vs.dark && cfg.DarkMode == DarkModeClass {
+		sb.WriteString(".dark ")
+	}
+	sb.WriteString(".")
+	sb.WriteString(identifier)
+	for _, idx := range vs.states {
+		sb.WriteString(StateVariants[idx].Pseudo)
+	}
+	return sb.String()
+}
+
+// mediaQuery returns the media query the rule belongs to,
+// or an empty string for rules outside of media queries.
+func (vs variantSet) mediaQuery(cfg *Config) string {
+	var conds []string
+	if vs.breakpoint != -1 {
+		conds = append(conds, fmt.Sprintf("(min-width: %s)", cfg.Breakpoints[vs.breakpoint].MinWidth))
+	}
+	if vs.dark && cfg.DarkMode != DarkModeClass {
+		conds = append(conds, "(prefers-color-scheme: dark)")
+	}
+	return strings.Join(conds, " and ")
+}
+
+// compareVariantSets orders rules by breakpoint, then rules without
+// the dark variant before rules with it, then rules without states
+// before rules with states, and rules with states by the precedence
+// of their states.
+func compareVariantSets(a, b variantSet) int {
+	if a.breakpoint != b.breakpoint {
+		return a.breakpoint - b.breakpoint
+	}
+	if a.dark != b.dark {
+		if b.dark {
+			return -1
+		}
+		return 1
+	}
+	return slices.Compare(slices.Sorted(slices.Values(a.states)), slices.Sorted(slices.Values(b.states)))
+}
+
+func (c *Config) classifyVariants(variants []string) (variantSet, error) {
+	vs := variantSet{breakpoint: -1}
+	for _, v := range variants {
+		if v == DarkVariant {
+			if vs.dark {
+				return variantSet{}, fmt.Errorf("duplicate variant: %s", v)
+			}
+			vs.dark = true
+			continue
+		}
+		if idx := c.breakpointIndex(v); idx != -1 {
+			if vs.breakpoint != -1 {
+				return variantSet{}, fmt.Errorf("multiple breakpoint variants: %s", strings.Join(variants, ":"))
+			}
+			vs.breakpoint = idx
+			continue
+		}
+		if idx := stateIndex(v); idx != -1 {
+			if slices.Contains(vs.states, idx) {
+				return variantSet{}, fmt.Errorf("duplicate variant: %s", v)
+			}
+			vs.states = append(vs.states, idx)
+			continue
+		}
+		return variantSet{}, fmt.Errorf("%w: %s", ErrUnknownVariant, v)
+	}
+	return vs, nil
+}
+
+func (c *Config) variantNames() []string {
+	names := make([]string, 0, len(c.Breakpoints)+len(StateVariants)+1)
+	names = append(names, DarkVariant)
+	for _, bp := range c.Breakpoints {
+		names = append(names, bp.Name)
+	}
+	for _, sv := range StateVariants {
+		names = append(names, sv.Name)
+	}
+	return names
+}